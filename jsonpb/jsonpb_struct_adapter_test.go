@@ -0,0 +1,58 @@
+package jsonpb
+
+import (
+	"testing"
+
+	pb "github.com/gogo/protobuf/jsonpb/jsonpb_test_proto"
+	"github.com/gogo/protobuf/types"
+)
+
+// Struct and ListValue do not implement JSONPBMarshaler/JSONPBUnmarshaler.
+// They don't need to: marshalObject and unmarshalValue already special-case
+// them by XXX_WellKnownType() ahead of the generic reflection path, which is
+// how they get their "fields map" / "values slice" JSON shape instead of the
+// shape their generated struct tags would otherwise produce. Adding
+// JSONPBMarshaler to Struct would take priority over that existing switch
+// (it's checked first) and would have to reimplement Any-wrapping and
+// indent/EmitDefaults handling to avoid regressing it, for no behavioral
+// gain. These tests confirm the existing path already round-trips a Struct
+// field embedded in a message under the options most likely to affect it.
+func TestStructFieldMarshalsViaWellKnownTypeSwitch(t *testing.T) {
+	m := &Marshaler{OrigName: true, EmitDefaults: true}
+	msg := &pb.KnownTypes{
+		St: &types.Struct{
+			Fields: map[string]*types.Value{
+				"one": {Kind: &types.Value_StringValue{StringValue: "loneliest number"}},
+				"two": {Kind: &types.Value_NullValue{}},
+			},
+		},
+	}
+
+	got, err := m.MarshalToString(msg)
+	if err != nil {
+		t.Fatalf("MarshalToString() error: %v", err)
+	}
+	want := `{"an":null,"dur":null,"st":{"one":"loneliest number","two":null},"ts":null,"lv":null,"val":null,"dbl":null,"flt":null,"i64":null,"u64":null,"i32":null,"u32":null,"bool":null,"str":null,"bytes":null}`
+	if got != want {
+		t.Errorf("MarshalToString() = %s, want %s", got, want)
+	}
+}
+
+func TestStructFieldUnmarshalsViaWellKnownTypeSwitch(t *testing.T) {
+	const input = `{"st":{"one":"loneliest number","two":null}}`
+
+	var msg pb.KnownTypes
+	if err := UnmarshalString(input, &msg); err != nil {
+		t.Fatalf("UnmarshalString() error: %v", err)
+	}
+
+	want := &types.Struct{
+		Fields: map[string]*types.Value{
+			"one": {Kind: &types.Value_StringValue{StringValue: "loneliest number"}},
+			"two": {Kind: &types.Value_NullValue{}},
+		},
+	}
+	if got := msg.St; got.String() != want.String() {
+		t.Errorf("St = %v, want %v", got, want)
+	}
+}
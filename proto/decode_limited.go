@@ -0,0 +1,28 @@
+package proto
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+// ErrReadLimitExceeded is returned by DecodeLimited when r produces more
+// than maxBytes before EOF. It is distinct from ErrTooLarge, which reports
+// the unrelated case of Marshal producing an over 2GB message.
+var ErrReadLimitExceeded = errors.New("proto: payload too large")
+
+// DecodeLimited reads at most maxBytes from r and unmarshals them into m,
+// guarding against an oversized request body consuming unbounded memory
+// before Unmarshal ever runs. It returns ErrReadLimitExceeded if r still
+// has data left after maxBytes have been read.
+func DecodeLimited(r io.Reader, maxBytes int64, m Message) error {
+	lr := &io.LimitedReader{R: r, N: maxBytes + 1}
+	buf, err := ioutil.ReadAll(lr)
+	if err != nil {
+		return err
+	}
+	if int64(len(buf)) > maxBytes {
+		return ErrReadLimitExceeded
+	}
+	return Unmarshal(buf, m)
+}
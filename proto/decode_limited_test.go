@@ -0,0 +1,39 @@
+package proto_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	pb "github.com/gogo/protobuf/proto/proto3_proto"
+)
+
+func TestDecodeLimitedUnderLimit(t *testing.T) {
+	in := &pb.Message{Name: "Kirk", HeightInCm: 178}
+	data, err := proto.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out pb.Message
+	if err := proto.DecodeLimited(bytes.NewReader(data), int64(len(data)), &out); err != nil {
+		t.Fatalf("DecodeLimited() = %v, want nil", err)
+	}
+	if !proto.Equal(in, &out) {
+		t.Errorf("DecodeLimited() = %v, want %v", &out, in)
+	}
+}
+
+func TestDecodeLimitedOverLimit(t *testing.T) {
+	in := &pb.Message{Name: "Kirk", HeightInCm: 178}
+	data, err := proto.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out pb.Message
+	err = proto.DecodeLimited(bytes.NewReader(data), int64(len(data))-1, &out)
+	if err != proto.ErrReadLimitExceeded {
+		t.Fatalf("DecodeLimited() = %v, want ErrReadLimitExceeded", err)
+	}
+}
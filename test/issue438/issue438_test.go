@@ -0,0 +1,31 @@
+package issue438
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+// TestStructFieldRoundTripsThroughProtoText guards against a regression
+// where *types.Struct implemented encoding.TextMarshaler: proto's text
+// marshaler special-cases that interface by dumping the marshaler's output
+// raw inside the text-format braces for the field, which corrupted
+// proto.MarshalText/UnmarshalText for any message embedding a Struct.
+func TestStructFieldRoundTripsThroughProtoText(t *testing.T) {
+	st, err := types.NewStruct(map[string]interface{}{"a": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := &Types{St: st}
+
+	text := proto.MarshalTextString(in)
+
+	var out Types
+	if err := proto.UnmarshalText(text, &out); err != nil {
+		t.Fatalf("UnmarshalText(%q): %v", text, err)
+	}
+	if !proto.Equal(in, &out) {
+		t.Errorf("round trip through text format = %+v, want %+v (text: %q)", &out, in, text)
+	}
+}
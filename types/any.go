@@ -69,15 +69,32 @@ func MarshalAny(pb proto.Message) (*Any, error) {
 	return &Any{TypeUrl: googleApis + proto.MessageName(pb), Value: value}, nil
 }
 
+// MarshalAnyWithPrefix is like MarshalAny, but builds the type URL from
+// prefix instead of the default "type.googleapis.com/". prefix should
+// normally end in "/"; one is added if it doesn't. This is for callers with
+// their own schema registry host. Unpacking (AnyMessageName, MessageName,
+// Is, UnmarshalAny) only ever looks at the text after the last "/", so Anys
+// packed this way unpack the same as ones packed with MarshalAny.
+func MarshalAnyWithPrefix(prefix string, pb proto.Message) (*Any, error) {
+	value, err := proto.Marshal(pb)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &Any{TypeUrl: prefix + proto.MessageName(pb), Value: value}, nil
+}
+
 // DynamicAny is a value that can be passed to UnmarshalAny to automatically
 // allocate a proto.Message for the type specified in a google.protobuf.Any
 // message. The allocated message is stored in the embedded proto.Message.
 //
 // Example:
 //
-//   var x ptypes.DynamicAny
-//   if err := ptypes.UnmarshalAny(a, &x); err != nil { ... }
-//   fmt.Printf("unmarshaled message: %v", x.Message)
+//	var x ptypes.DynamicAny
+//	if err := ptypes.UnmarshalAny(a, &x); err != nil { ... }
+//	fmt.Printf("unmarshaled message: %v", x.Message)
 type DynamicAny struct {
 	proto.Message
 }
@@ -138,3 +155,33 @@ func Is(any *Any, pb proto.Message) bool {
 	prefix := len(any.TypeUrl) - len(name)
 	return prefix >= 1 && any.TypeUrl[prefix-1] == '/' && any.TypeUrl[prefix:] == name
 }
+
+// MessageName returns the fully-qualified message name embedded in any's
+// type URL, i.e. everything after the last "/". It returns the empty
+// string if any is nil or its type URL has no "/".
+func (any *Any) MessageName() string {
+	name, err := AnyMessageName(any)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// Is reports whether any holds a message of the same type as m.
+func (any *Any) Is(m proto.Message) bool {
+	return Is(any, m)
+}
+
+// UnmarshalNew resolves any's type URL against the registered proto types,
+// allocates a message of that type, and unmarshals any's value into it. It
+// returns an error if the type isn't registered or unmarshaling fails.
+func (any *Any) UnmarshalNew() (proto.Message, error) {
+	pb, err := EmptyAny(any)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(any.GetValue(), pb); err != nil {
+		return nil, err
+	}
+	return pb, nil
+}
@@ -131,6 +131,83 @@ func TestEmpty(t *testing.T) {
 	}
 }
 
+func TestUnmarshalNew(t *testing.T) {
+	want := &pb.FileDescriptorProto{Name: proto.String("foo")}
+	a, err := MarshalAny(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := a.UnmarshalNew()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("UnmarshalNew() = %v, want %v", got, want)
+	}
+
+	a.TypeUrl = "type.googleapis.com/google.protobuf.TestAny"
+	if _, err := a.UnmarshalNew(); err == nil {
+		t.Errorf("UnmarshalNew() for unregistered type %q succeeded, want error", a.TypeUrl)
+	}
+}
+
+func TestAnyMessageNameAndIs(t *testing.T) {
+	m := &pb.FileDescriptorProto{}
+	wantName := proto.MessageName(m)
+
+	std, err := MarshalAny(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := std.MessageName(); got != wantName {
+		t.Errorf("MessageName() = %q, want %q", got, wantName)
+	}
+	if !std.Is(m) {
+		t.Errorf("Is(%v) = false, want true", m)
+	}
+
+	custom := &Any{TypeUrl: "my.internal.registry/" + wantName}
+	if got := custom.MessageName(); got != wantName {
+		t.Errorf("custom prefix: MessageName() = %q, want %q", got, wantName)
+	}
+	if !custom.Is(m) {
+		t.Errorf("custom prefix: Is(%v) = false, want true", m)
+	}
+}
+
+func TestMarshalAnyWithPrefix(t *testing.T) {
+	m := &pb.FileDescriptorProto{}
+	a, err := MarshalAnyWithPrefix("https://schema.example.com/types", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantURL := "https://schema.example.com/types/" + proto.MessageName(m)
+	if a.TypeUrl != wantURL {
+		t.Errorf("TypeUrl = %q, want %q", a.TypeUrl, wantURL)
+	}
+	if got := a.MessageName(); got != proto.MessageName(m) {
+		t.Errorf("MessageName() = %q, want %q", got, proto.MessageName(m))
+	}
+	if !a.Is(m) {
+		t.Error("Is(m) = false, want true")
+	}
+
+	var unpacked pb.FileDescriptorProto
+	if err := UnmarshalAny(a, &unpacked); err != nil {
+		t.Errorf("UnmarshalAny: %v", err)
+	}
+
+	// A prefix already ending in "/" should not gain a second one.
+	a2, err := MarshalAnyWithPrefix("https://schema.example.com/types/", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a2.TypeUrl != wantURL {
+		t.Errorf("TypeUrl with trailing slash in prefix = %q, want %q", a2.TypeUrl, wantURL)
+	}
+}
+
 func TestEmptyCornerCases(t *testing.T) {
 	_, err := EmptyAny(nil)
 	if err == nil {
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// jsonNumberThreshold is the magnitude above which a whole-valued
+// NumberValue risks printing in scientific notation (e.g. "1e+15") when
+// re-marshaled by "encoding/json", since float64 can represent integers
+// exactly only up to 2^53 (~9.007e15) and Go's float formatter switches
+// to exponential form for sufficiently large magnitudes.
+const jsonNumberThreshold = 1e15
+
+// AsInterfaceOptions configures AsInterfaceWithOptions, AsMapWithOptions,
+// and AsSliceWithOptions.
+type AsInterfaceOptions struct {
+	// LargeIntegersAsJSONNumber, when set, converts a whole-valued
+	// NumberValue whose magnitude is at least jsonNumberThreshold into a
+	// json.Number holding its plain decimal digits, instead of a float64.
+	// Re-marshaling a json.Number through "encoding/json" emits it
+	// verbatim, avoiding the exponent notation a large float64 could
+	// otherwise print as, which matters for IDs that must survive a
+	// round trip through generic JSON tooling unchanged.
+	LargeIntegersAsJSONNumber bool
+}
+
+// AsInterfaceWithOptions is like AsInterface, but converts large integral
+// NumberValues to json.Number according to o.
+func (x *Value) AsInterfaceWithOptions(o AsInterfaceOptions) interface{} {
+	if v, ok := x.GetKind().(*Value_NumberValue); ok && v != nil {
+		f := v.NumberValue
+		if o.LargeIntegersAsJSONNumber && f == math.Trunc(f) && !math.IsInf(f, 0) && math.Abs(f) >= jsonNumberThreshold {
+			return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+		}
+	}
+	switch v := x.GetKind().(type) {
+	case *Value_StructValue:
+		if v != nil {
+			return v.StructValue.AsMapWithOptions(o)
+		}
+	case *Value_ListValue:
+		if v != nil {
+			return v.ListValue.AsSliceWithOptions(o)
+		}
+	}
+	return x.AsInterface()
+}
+
+// AsMapWithOptions is like AsMap, but converts large integral NumberValues
+// to json.Number according to o.
+func (x *Struct) AsMapWithOptions(o AsInterfaceOptions) map[string]interface{} {
+	vs := make(map[string]interface{})
+	for k, v := range x.GetFields() {
+		vs[k] = v.AsInterfaceWithOptions(o)
+	}
+	return vs
+}
+
+// AsSliceWithOptions is like AsSlice, but converts large integral
+// NumberValues to json.Number according to o.
+func (x *ListValue) AsSliceWithOptions(o AsInterfaceOptions) []interface{} {
+	values := x.GetValues()
+	vs := make([]interface{}, len(values))
+	for i, v := range values {
+		vs[i] = v.AsInterfaceWithOptions(o)
+	}
+	return vs
+}
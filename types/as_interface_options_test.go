@@ -0,0 +1,59 @@
+package types_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestAsInterfaceWithOptionsLargeIntegerNoExponent(t *testing.T) {
+	v := types.NewNumberValue(1234567890123456.0)
+
+	got := v.AsInterfaceWithOptions(types.AsInterfaceOptions{LargeIntegersAsJSONNumber: true})
+	if _, ok := got.(json.Number); !ok {
+		t.Fatalf("AsInterfaceWithOptions() = %T, want json.Number", got)
+	}
+
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(string(b), "eE") {
+		t.Errorf("Marshal(%v) = %s, want no exponent notation", got, b)
+	}
+	if want := "1234567890123456"; string(b) != want {
+		t.Errorf("Marshal(%v) = %s, want %s", got, b, want)
+	}
+}
+
+func TestAsInterfaceWithOptionsSmallNumberUnaffected(t *testing.T) {
+	v := types.NewNumberValue(3.5)
+	got := v.AsInterfaceWithOptions(types.AsInterfaceOptions{LargeIntegersAsJSONNumber: true})
+	if f, ok := got.(float64); !ok || f != 3.5 {
+		t.Errorf("AsInterfaceWithOptions() = %v (%T), want float64(3.5)", got, got)
+	}
+}
+
+func TestAsMapWithOptionsRecurses(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"id":     1234567890123456.0,
+		"nested": map[string]interface{}{"id": 9876543210987654.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := s.AsMapWithOptions(types.AsInterfaceOptions{LargeIntegersAsJSONNumber: true})
+	if _, ok := m["id"].(json.Number); !ok {
+		t.Errorf("m[\"id\"] = %T, want json.Number", m["id"])
+	}
+	nested, ok := m["nested"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("m[\"nested\"] = %T, want map[string]interface{}", m["nested"])
+	}
+	if _, ok := nested["id"].(json.Number); !ok {
+		t.Errorf("nested[\"id\"] = %T, want json.Number", nested["id"])
+	}
+}
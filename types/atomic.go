@@ -0,0 +1,28 @@
+package types
+
+import "sync/atomic"
+
+// AtomicStruct holds a *Struct that can be swapped and read concurrently
+// without locking, for the common pattern of a background refresher
+// rebuilding a config Struct while readers keep using the previous
+// version until the next Store. The zero value's Load returns nil.
+type AtomicStruct struct {
+	v atomic.Value // holds *Struct
+}
+
+// Load returns the most recently stored Struct, or nil if Store has never
+// been called.
+func (a *AtomicStruct) Load() *Struct {
+	v := a.v.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(*Struct)
+}
+
+// Store atomically replaces the held Struct with s. Callers should treat s
+// as immutable after passing it to Store, e.g. by building it fresh or
+// calling Clone first, since readers may still be using it.
+func (a *AtomicStruct) Store(s *Struct) {
+	a.v.Store(s)
+}
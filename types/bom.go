@@ -0,0 +1,16 @@
+package types
+
+// utf8BOM is the UTF-8 encoding of U+FEFF BYTE ORDER MARK, which some
+// Windows-generated tools prepend to JSON files even though JSON itself has
+// no notion of a BOM.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// stripBOM returns data with a leading UTF-8 BOM removed, if present. It is
+// applied once at the root of a decode, not at every nested call, since a
+// BOM can only legitimately appear at the very start of a document.
+func stripBOM(data []byte) []byte {
+	if len(data) >= len(utf8BOM) && data[0] == utf8BOM[0] && data[1] == utf8BOM[1] && data[2] == utf8BOM[2] {
+		return data[len(utf8BOM):]
+	}
+	return data
+}
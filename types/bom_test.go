@@ -0,0 +1,38 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestUnmarshalJSONStripsLeadingBOM(t *testing.T) {
+	var v types.Value
+	if err := v.UnmarshalJSON([]byte("\ufeff{\"a\":1}")); err != nil {
+		t.Fatal(err)
+	}
+	if got := v.GetStructValue().GetFields()["a"].GetNumberValue(); got != 1 {
+		t.Errorf("a = %v, want 1", got)
+	}
+}
+
+func TestUnmarshalJSONToleratesSurroundingWhitespace(t *testing.T) {
+	var v types.Value
+	if err := v.UnmarshalJSON([]byte("  \n\t{\"a\":1}\n  ")); err != nil {
+		t.Fatal(err)
+	}
+	if got := v.GetStructValue().GetFields()["a"].GetNumberValue(); got != 1 {
+		t.Errorf("a = %v, want 1", got)
+	}
+}
+
+func TestDecoderDecodeStripsLeadingBOM(t *testing.T) {
+	d := types.NewDecoder()
+	var s types.Struct
+	if err := d.Decode([]byte("\ufeff{\"a\":1}"), &s); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.GetFields()["a"].GetNumberValue(); got != 1 {
+		t.Errorf("a = %v, want 1", got)
+	}
+}
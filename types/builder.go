@@ -0,0 +1,58 @@
+package types
+
+// Builder provides a fluent API for constructing a Struct, which is more
+// readable than a literal map[string]interface{} for hand-written nested
+// fixtures in tests. Errors (such as an invalid UTF-8 string or value type)
+// are accumulated and returned by Build, rather than from each call, so
+// that a chain of Set calls can be written without checking every step.
+type Builder struct {
+	fields map[string]*Value
+	err    error
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{fields: make(map[string]*Value)}
+}
+
+// Set converts v using NewValue and stores it under key, returning b for
+// chaining. If v cannot be converted, the error is recorded and returned by
+// Build; subsequent calls on b are no-ops.
+func (b *Builder) Set(key string, v interface{}) *Builder {
+	if b.err != nil {
+		return b
+	}
+	nv, err := NewValue(v)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.fields[key] = nv
+	return b
+}
+
+// SetStruct builds a nested Struct with a fresh Builder passed to fn and
+// stores it under key, returning b for chaining.
+func (b *Builder) SetStruct(key string, fn func(*Builder)) *Builder {
+	if b.err != nil {
+		return b
+	}
+	nested := NewBuilder()
+	fn(nested)
+	s, err := nested.Build()
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.fields[key] = NewStructValue(s)
+	return b
+}
+
+// Build returns the constructed Struct, or the first error recorded by any
+// Set or SetStruct call in the chain.
+func (b *Builder) Build() (*Struct, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return &Struct{Fields: b.fields}, nil
+}
@@ -0,0 +1,46 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestBuilderBuildsNestedStruct(t *testing.T) {
+	got, err := types.NewBuilder().
+		Set("name", "widget").
+		Set("price", 9.99).
+		SetStruct("dimensions", func(b *types.Builder) {
+			b.Set("width", 1.0).Set("height", 2.0)
+		}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := types.NewStruct(map[string]interface{}{
+		"name":  "widget",
+		"price": 9.99,
+		"dimensions": map[string]interface{}{
+			"width":  1.0,
+			"height": 2.0,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !got.Equal(want) {
+		t.Errorf("Builder result = %v, want %v", got, want)
+	}
+}
+
+func TestBuilderAccumulatesError(t *testing.T) {
+	_, err := types.NewBuilder().
+		Set("bad", make(chan int)).
+		Set("ignored", "never applied").
+		Build()
+	if err == nil {
+		t.Fatal("got nil error for unconvertible value, want error")
+	}
+}
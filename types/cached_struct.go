@@ -0,0 +1,86 @@
+package types
+
+import "sync"
+
+// CachedStruct wraps a *Struct and memoizes its JSON encoding, for hot
+// paths that marshal the same largely-immutable Struct repeatedly. The
+// cache is invalidated automatically by Set and Invalidate; it assumes the
+// caller never mutates the wrapped Struct's Fields map (or any Value
+// reachable from it) directly, since CachedStruct has no way to observe
+// that and would then serve stale bytes.
+//
+// A CachedStruct is safe for concurrent use: concurrent MarshalJSON calls
+// that hit the cache proceed without blocking each other.
+type CachedStruct struct {
+	mu     sync.RWMutex
+	s      *Struct
+	cached []byte
+	valid  bool
+}
+
+// NewCachedStruct returns a CachedStruct wrapping s.
+func NewCachedStruct(s *Struct) *CachedStruct {
+	return &CachedStruct{s: s}
+}
+
+// Struct returns the wrapped Struct. Callers must not mutate it directly;
+// use Set, or call Invalidate after a direct mutation.
+func (c *CachedStruct) Struct() *Struct {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.s
+}
+
+// MarshalJSON returns the cached JSON encoding of the wrapped Struct,
+// computing and caching it first if this is the first call since
+// construction or the last invalidation.
+func (c *CachedStruct) MarshalJSON() ([]byte, error) {
+	c.mu.RLock()
+	if c.valid {
+		b := c.cached
+		c.mu.RUnlock()
+		return b, nil
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.valid {
+		return c.cached, nil
+	}
+	b, err := c.s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	c.cached = b
+	c.valid = true
+	return b, nil
+}
+
+// Set converts v using NewValue and stores it under key, invalidating the
+// cache so the next MarshalJSON call recomputes it.
+func (c *CachedStruct) Set(key string, v interface{}) error {
+	nv, err := NewValue(v)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.s.Fields == nil {
+		c.s.Fields = make(map[string]*Value)
+	}
+	c.s.Fields[key] = nv
+	c.valid = false
+	c.cached = nil
+	return nil
+}
+
+// Invalidate discards the cached JSON encoding, forcing the next
+// MarshalJSON call to recompute it. Call this after mutating the wrapped
+// Struct directly (outside of Set).
+func (c *CachedStruct) Invalidate() {
+	c.mu.Lock()
+	c.valid = false
+	c.cached = nil
+	c.mu.Unlock()
+}
@@ -0,0 +1,75 @@
+package types_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestCachedStructMarshalJSONCachesAndInvalidates(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{"a": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := types.NewCachedStruct(s)
+
+	b1, err := c.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"a":1}`; string(b1) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", b1, want)
+	}
+
+	if err := c.Set("b", 2.0); err != nil {
+		t.Fatal(err)
+	}
+	b2, err := c.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"a":1,"b":2}`; string(b2) != want {
+		t.Errorf("MarshalJSON() after Set = %s, want %s", b2, want)
+	}
+}
+
+func TestCachedStructConcurrentMarshalJSON(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{"a": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := types.NewCachedStruct(s)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.MarshalJSON(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func structForCacheBenchmark() *types.Struct {
+	s, err := types.NewStruct(map[string]interface{}{
+		"a": 1.0, "b": "x", "c": true, "d": []interface{}{1.0, 2.0, 3.0},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func BenchmarkCachedStructMarshalJSON(b *testing.B) {
+	c := types.NewCachedStruct(structForCacheBenchmark())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package types
+
+// Structs, Values, and ListValues are plain Go maps and slices under the
+// hood and are not safe for concurrent mutation, or for concurrent reads
+// racing a mutation. Code that shares one across goroutines should either
+// treat it as immutable after construction, or use Snapshot (below) or
+// AtomicStruct (atomic.go) to hand out or swap whole copies instead of
+// mutating shared state in place.
+
+// Clone returns a deep copy of x. It is nil-safe.
+func (x *Struct) Clone() *Struct {
+	if x == nil {
+		return nil
+	}
+	out := &Struct{Fields: make(map[string]*Value, len(x.Fields))}
+	for k, v := range x.Fields {
+		out.Fields[k] = cloneValue(v)
+	}
+	return out
+}
+
+// Snapshot returns a deep copy of x, suitable for handing to another
+// goroutine to read freely while the original continues to be mutated.
+// It is an alias for Clone; see the note at the top of this file for why
+// Structs need this instead of being read concurrently in place.
+func (x *Struct) Snapshot() *Struct {
+	return x.Clone()
+}
+
+func cloneValue(v *Value) *Value {
+	if v == nil {
+		return nil
+	}
+	switch k := v.GetKind().(type) {
+	case *Value_NullValue:
+		return &Value{Kind: &Value_NullValue{NullValue: k.NullValue}}
+	case *Value_BoolValue:
+		return &Value{Kind: &Value_BoolValue{BoolValue: k.BoolValue}}
+	case *Value_NumberValue:
+		return &Value{Kind: &Value_NumberValue{NumberValue: k.NumberValue}}
+	case *Value_StringValue:
+		return &Value{Kind: &Value_StringValue{StringValue: k.StringValue}}
+	case *Value_StructValue:
+		return NewStructValue(k.StructValue.Clone())
+	case *Value_ListValue:
+		values := k.ListValue.GetValues()
+		cloned := make([]*Value, len(values))
+		for i, child := range values {
+			cloned[i] = cloneValue(child)
+		}
+		return NewListValue(&ListValue{Values: cloned})
+	default:
+		return &Value{}
+	}
+}
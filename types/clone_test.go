@@ -0,0 +1,44 @@
+package types
+
+import "testing"
+
+func TestStructCloneIsDeepAndIndependent(t *testing.T) {
+	orig, err := NewStruct(map[string]interface{}{
+		"a":      1.0,
+		"nested": map[string]interface{}{"b": "x"},
+		"list":   []interface{}{1.0, 2.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone := orig.Clone()
+	if !orig.Equal(clone) {
+		t.Fatalf("clone not equal to original: %v vs %v", clone, orig)
+	}
+
+	// Mutating the clone must not affect the original.
+	clone.Fields["a"] = NewNumberValue(99)
+	clone.Fields["nested"].GetStructValue().Fields["b"] = NewStringValue("mutated")
+	clone.Fields["list"].GetListValue().Values[0] = NewNumberValue(99)
+
+	if got := orig.Fields["a"].AsInterface(); got != 1.0 {
+		t.Errorf("original a mutated: got %v, want 1.0", got)
+	}
+	if got := orig.Fields["nested"].GetStructValue().Fields["b"].AsInterface(); got != "x" {
+		t.Errorf("original nested.b mutated: got %v, want x", got)
+	}
+	if got := orig.Fields["list"].GetListValue().Values[0].AsInterface(); got != 1.0 {
+		t.Errorf("original list[0] mutated: got %v, want 1.0", got)
+	}
+}
+
+func TestStructCloneNilSafe(t *testing.T) {
+	var nilStruct *Struct
+	if got := nilStruct.Clone(); got != nil {
+		t.Errorf("nil.Clone() = %v, want nil", got)
+	}
+	if got := nilStruct.Snapshot(); got != nil {
+		t.Errorf("nil.Snapshot() = %v, want nil", got)
+	}
+}
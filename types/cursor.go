@@ -0,0 +1,80 @@
+package types
+
+// Cursor is a fluent, nil-safe view onto a *Value, for navigating deeply
+// nested optional data without a GetFields/GetValues nil check at every
+// step. A Cursor obtained by stepping through a missing field, a
+// non-Struct/ListValue parent, or an out-of-range index is simply empty,
+// and every further step and terminal accessor on it reports a missing
+// result rather than panicking.
+//
+// Cursor complements Struct.Query: Query returns every match for a path
+// expression that may fan out through wildcards, while Cursor follows one
+// concrete path and is built for ergonomic chaining, e.g.
+// CursorOf(v).Field("a").Field("b").Index(0).String().
+type Cursor struct {
+	v *Value
+}
+
+// CursorOf returns a Cursor over v.
+func CursorOf(v *Value) Cursor {
+	return Cursor{v: v}
+}
+
+// Field steps into the Struct field key, returning an empty Cursor if the
+// current Value is not a StructValue or has no such field.
+func (c Cursor) Field(key string) Cursor {
+	sv, ok := c.v.GetKind().(*Value_StructValue)
+	if !ok || sv == nil {
+		return Cursor{}
+	}
+	return Cursor{v: sv.StructValue.GetFields()[key]}
+}
+
+// Index steps into the ListValue element i, returning an empty Cursor if
+// the current Value is not a ListValue or i is out of range.
+func (c Cursor) Index(i int) Cursor {
+	lv, ok := c.v.GetKind().(*Value_ListValue)
+	if !ok || lv == nil {
+		return Cursor{}
+	}
+	values := lv.ListValue.GetValues()
+	if i < 0 || i >= len(values) {
+		return Cursor{}
+	}
+	return Cursor{v: values[i]}
+}
+
+// Value returns the Value at c, and whether c refers to one at all.
+func (c Cursor) Value() (*Value, bool) {
+	if c.v == nil {
+		return nil, false
+	}
+	return c.v, true
+}
+
+// String returns the string at c, and whether c refers to a StringValue.
+func (c Cursor) String() (string, bool) {
+	sv, ok := c.v.GetKind().(*Value_StringValue)
+	if !ok || sv == nil {
+		return "", false
+	}
+	return sv.StringValue, true
+}
+
+// Number returns the number at c, and whether c refers to a NumberValue.
+func (c Cursor) Number() (float64, bool) {
+	nv, ok := c.v.GetKind().(*Value_NumberValue)
+	if !ok || nv == nil {
+		return 0, false
+	}
+	return nv.NumberValue, true
+}
+
+// Bool returns the bool at c, and whether c refers to a BoolValue.
+func (c Cursor) Bool() (bool, bool) {
+	bv, ok := c.v.GetKind().(*Value_BoolValue)
+	if !ok || bv == nil {
+		return false, false
+	}
+	return bv.BoolValue, true
+}
@@ -0,0 +1,44 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestCursorNavigatesNestedPath(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": []interface{}{"x", "y"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := types.CursorOf(types.NewStructValue(s)).Field("a").Field("b").Index(1)
+	got, ok := c.String()
+	if !ok || got != "y" {
+		t.Errorf("String() = %q, %v, want %q, true", got, ok, "y")
+	}
+}
+
+func TestCursorMissingPathYieldsZeroResult(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := types.CursorOf(types.NewStructValue(s)).Field("a").Field("missing").Index(0)
+	if got, ok := c.String(); ok || got != "" {
+		t.Errorf("String() = %q, %v, want %q, false", got, ok, "")
+	}
+	if got, ok := c.Number(); ok || got != 0 {
+		t.Errorf("Number() = %v, %v, want 0, false", got, ok)
+	}
+	if _, ok := c.Value(); ok {
+		t.Errorf("Value() ok = true, want false")
+	}
+}
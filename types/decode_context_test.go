@@ -0,0 +1,75 @@
+package types_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// countingContext reports context.Canceled from Err once it has been asked
+// cancelAt times, letting a test deterministically simulate cancellation
+// partway through a decode without racing a real timer against it.
+type countingContext struct {
+	context.Context
+	calls    *int
+	cancelAt int
+}
+
+func (c countingContext) Err() error {
+	*c.calls++
+	if *c.calls >= c.cancelAt {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestDecodeContextReturnsCanceledMidDecode(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, `"k%d":%d`, i, i)
+	}
+	buf.WriteString("}")
+
+	calls := 0
+	ctx := countingContext{Context: context.Background(), calls: &calls, cancelAt: 5}
+
+	var s types.Struct
+	err := types.DecodeContext(ctx, &buf, &s)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("DecodeContext() error = %v, want context.Canceled", err)
+	}
+	if len(s.Fields) >= 1000 {
+		t.Errorf("len(Fields) = %d, want decode to have stopped early", len(s.Fields))
+	}
+}
+
+func TestDecodeContextSucceedsWithoutCancellation(t *testing.T) {
+	r := bytes.NewBufferString(`{"a":1,"b":[1,2,3]}`)
+	var s types.Struct
+	if err := types.DecodeContext(context.Background(), r, &s); err != nil {
+		t.Fatalf("DecodeContext() error = %v, want nil", err)
+	}
+	if got := len(s.Fields); got != 2 {
+		t.Errorf("len(Fields) = %d, want 2", got)
+	}
+}
+
+func TestDecodeContextRejectsAlreadyCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := bytes.NewBufferString(`{"a":1}`)
+	var s types.Struct
+	err := types.DecodeContext(ctx, r, &s)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("DecodeContext() error = %v, want context.Canceled", err)
+	}
+}
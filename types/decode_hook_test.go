@@ -0,0 +1,60 @@
+package types_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestDecoderDecodeHookCoercesSpecificKey(t *testing.T) {
+	opts := types.UnmarshalOptions{
+		DecodeHook: func(path string, raw json.RawMessage) (*types.Value, bool, error) {
+			if path != "amount" {
+				return nil, false, nil
+			}
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, false, err
+			}
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, false, err
+			}
+			return types.NewNumberValue(f), true, nil
+		},
+	}
+
+	var s types.Struct
+	d := types.NewDecoder()
+	d.Options = opts
+	if err := d.Decode([]byte(`{"amount":"12.5","other":"12.5"}`), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.Fields["amount"].GetNumberValue(); got != 12.5 {
+		t.Errorf("amount = %v, want 12.5 (NumberValue)", s.Fields["amount"])
+	}
+	if got := s.Fields["other"].GetStringValue(); got != "12.5" {
+		t.Errorf("other = %v, want \"12.5\" (StringValue, untouched)", s.Fields["other"])
+	}
+}
+
+func TestDecoderDecodeHookDeclineFallsThroughToDefault(t *testing.T) {
+	opts := types.UnmarshalOptions{
+		DecodeHook: func(path string, raw json.RawMessage) (*types.Value, bool, error) {
+			return nil, false, nil
+		},
+	}
+
+	var v types.Value
+	d := types.NewDecoder()
+	d.Options = opts
+	if err := d.Decode([]byte(`42`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.GetNumberValue() != 42 {
+		t.Errorf("got %v, want NumberValue(42)", &v)
+	}
+}
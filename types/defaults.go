@@ -0,0 +1,33 @@
+package types
+
+// ApplyDefaults returns a new Struct with every field of defaults present,
+// except where user already has that field, in which case user's value
+// wins; fields present in both as Structs are merged recursively the same
+// way. Neither user nor defaults is mutated.
+//
+// A field that user sets explicitly to null is treated as user's value,
+// not filled in from defaults: ApplyDefaults only fills gaps where the key
+// is altogether absent from user, so an explicit null is an override, the
+// same distinction OmitEmptyNulls draws elsewhere in this package between
+// "absent" and "present but null".
+func ApplyDefaults(user, defaults *Struct) *Struct {
+	out := &Struct{Fields: make(map[string]*Value, defaults.Len()+user.Len())}
+	for k, dv := range defaults.GetFields() {
+		out.Fields[k] = cloneValue(dv)
+	}
+	for k, uv := range user.GetFields() {
+		dv, ok := out.Fields[k]
+		if !ok {
+			out.Fields[k] = uv
+			continue
+		}
+		dStruct, dIsStruct := dv.GetKind().(*Value_StructValue)
+		uStruct, uIsStruct := uv.GetKind().(*Value_StructValue)
+		if dIsStruct && uIsStruct {
+			out.Fields[k] = NewStructValue(ApplyDefaults(uStruct.StructValue, dStruct.StructValue))
+			continue
+		}
+		out.Fields[k] = uv
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+func TestApplyDefaultsFillsGapsRecursively(t *testing.T) {
+	defaults, err := types.NewStruct(map[string]interface{}{
+		"a": 1.0,
+		"nested": map[string]interface{}{
+			"x": 1.0,
+			"y": 2.0,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := types.NewStruct(map[string]interface{}{
+		"b": 2.0,
+		"nested": map[string]interface{}{
+			"x": 10.0,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := types.ApplyDefaults(user, defaults)
+	want, err := types.NewStruct(map[string]interface{}{
+		"a": 1.0,
+		"b": 2.0,
+		"nested": map[string]interface{}{
+			"x": 10.0,
+			"y": 2.0,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("ApplyDefaults() = %v, want %v", got, want)
+	}
+}
+
+func TestApplyDefaultsExplicitNullOverrides(t *testing.T) {
+	defaults, err := types.NewStruct(map[string]interface{}{"a": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := types.NewStruct(map[string]interface{}{"a": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := types.ApplyDefaults(user, defaults)
+	want, err := types.NewStruct(map[string]interface{}{"a": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("ApplyDefaults() = %v, want %v", got, want)
+	}
+}
+
+func TestApplyDefaultsDoesNotMutateInputs(t *testing.T) {
+	defaults, err := types.NewStruct(map[string]interface{}{"a": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := types.NewStruct(map[string]interface{}{"b": 2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types.ApplyDefaults(user, defaults)
+
+	if _, ok := defaults.Fields["b"]; ok {
+		t.Errorf("defaults was mutated: %v", defaults)
+	}
+	if _, ok := user.Fields["a"]; ok {
+		t.Errorf("user was mutated: %v", user)
+	}
+}
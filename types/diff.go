@@ -0,0 +1,105 @@
+package types
+
+import (
+	"sort"
+	"strings"
+)
+
+// DiffEntry describes one field-level difference found by Diff. Exactly
+// one of Old and New is nil for an added or removed field; both are set,
+// and unequal, for a changed one.
+type DiffEntry struct {
+	Path string
+	Old  *Value
+	New  *Value
+}
+
+// Diff compares a and b field by field, recursing into nested Structs,
+// and returns one DiffEntry per leaf-level difference, sorted by Path.
+// Lists are compared as whole values (an element-order change is a
+// "changed" entry on the list's own path, not per-element), since
+// ListValue has no inherent key to align elements by.
+func Diff(a, b *Struct) []DiffEntry {
+	var entries []DiffEntry
+	diffStructs(a, b, "", &entries)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries
+}
+
+func diffStructs(a, b *Struct, path string, entries *[]DiffEntry) {
+	af, bf := a.GetFields(), b.GetFields()
+	keys := make(map[string]struct{}, len(af)+len(bf))
+	for k := range af {
+		keys[k] = struct{}{}
+	}
+	for k := range bf {
+		keys[k] = struct{}{}
+	}
+	for k := range keys {
+		av, aok := af[k]
+		bv, bok := bf[k]
+		fieldPath := joinPath(path, k)
+		switch {
+		case !aok:
+			*entries = append(*entries, DiffEntry{Path: fieldPath, New: bv})
+		case !bok:
+			*entries = append(*entries, DiffEntry{Path: fieldPath, Old: av})
+		default:
+			diffValues(av, bv, fieldPath, entries)
+		}
+	}
+}
+
+func diffValues(a, b *Value, path string, entries *[]DiffEntry) {
+	as, aIsStruct := a.GetKind().(*Value_StructValue)
+	bs, bIsStruct := b.GetKind().(*Value_StructValue)
+	if aIsStruct && bIsStruct {
+		diffStructs(as.StructValue, bs.StructValue, path, entries)
+		return
+	}
+	if !a.Equal(b) {
+		*entries = append(*entries, DiffEntry{Path: path, Old: a, New: b})
+	}
+}
+
+// DiffText renders Diff(a, b) as human-readable unified-diff-style text:
+// one line per DiffEntry, sorted by path, with a removed-value line
+// prefixed "-" and/or an added-value line prefixed "+". A changed field
+// (both Old and New set) renders both lines; an added or removed field
+// renders only the corresponding one. Values are rendered with
+// MarshalJSON for a stable, canonical form.
+func DiffText(a, b *Struct) string {
+	var buf strings.Builder
+	for i, e := range Diff(a, b) {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		if e.Old != nil {
+			buf.WriteString("-")
+			buf.WriteString(e.Path)
+			buf.WriteString(": ")
+			buf.Write(mustMarshalJSON(e.Old))
+			if e.New != nil {
+				buf.WriteByte('\n')
+			}
+		}
+		if e.New != nil {
+			buf.WriteString("+")
+			buf.WriteString(e.Path)
+			buf.WriteString(": ")
+			buf.Write(mustMarshalJSON(e.New))
+		}
+	}
+	return buf.String()
+}
+
+// mustMarshalJSON renders v for display purposes, falling back to a
+// placeholder rather than panicking if MarshalJSON ever errors (which it
+// does not, for any Value reachable through Diff).
+func mustMarshalJSON(v *Value) []byte {
+	b, err := v.MarshalJSON()
+	if err != nil {
+		return []byte("<error>")
+	}
+	return b
+}
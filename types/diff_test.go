@@ -0,0 +1,65 @@
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestDiffAddedRemovedChanged(t *testing.T) {
+	a, err := types.NewStruct(map[string]interface{}{
+		"removed": "gone",
+		"changed": 1.0,
+		"same":    "x",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := types.NewStruct(map[string]interface{}{
+		"added":   "new",
+		"changed": 2.0,
+		"same":    "x",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := types.Diff(a, b)
+	byPath := make(map[string]types.DiffEntry)
+	for _, e := range entries {
+		byPath[e.Path] = e
+	}
+
+	if _, ok := byPath["same"]; ok {
+		t.Error("Diff() included unchanged field \"same\"")
+	}
+	if e, ok := byPath["removed"]; !ok || e.New != nil {
+		t.Errorf("Diff()[\"removed\"] = %+v, want Old set, New nil", e)
+	}
+	if e, ok := byPath["added"]; !ok || e.Old != nil {
+		t.Errorf("Diff()[\"added\"] = %+v, want New set, Old nil", e)
+	}
+	if e, ok := byPath["changed"]; !ok || e.Old == nil || e.New == nil {
+		t.Errorf("Diff()[\"changed\"] = %+v, want both Old and New set", e)
+	}
+}
+
+func TestDiffTextContainsExpectedLines(t *testing.T) {
+	a, err := types.NewStruct(map[string]interface{}{"name": "old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := types.NewStruct(map[string]interface{}{"name": "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := types.DiffText(a, b)
+	if !strings.Contains(text, `-name: "old"`) {
+		t.Errorf("DiffText() = %q, want it to contain -name: \"old\"", text)
+	}
+	if !strings.Contains(text, `+name: "new"`) {
+		t.Errorf("DiffText() = %q, want it to contain +name: \"new\"", text)
+	}
+}
@@ -98,3 +98,43 @@ func DurationProto(d time.Duration) *Duration {
 		Nanos:   int32(nanos),
 	}
 }
+
+// DurationFromGo is an alias for DurationProto, provided for symmetry with
+// ToGoDuration.
+func DurationFromGo(d time.Duration) *Duration {
+	return DurationProto(d)
+}
+
+// Add returns the sum of d and other as a new Duration. It does not
+// validate that the inputs or the result are within the valid Duration
+// range; use ToGoDuration to detect overflow.
+func (d *Duration) Add(other *Duration) *Duration {
+	sum := &Duration{
+		Seconds: d.GetSeconds() + other.GetSeconds(),
+		Nanos:   d.GetNanos() + other.GetNanos(),
+	}
+	if sum.Nanos >= 1e9 {
+		sum.Nanos -= 1e9
+		sum.Seconds++
+	} else if sum.Nanos <= -1e9 {
+		sum.Nanos += 1e9
+		sum.Seconds--
+	}
+	// Normalize so Seconds and Nanos carry the same sign, as required by
+	// validateDuration.
+	if sum.Seconds > 0 && sum.Nanos < 0 {
+		sum.Seconds--
+		sum.Nanos += 1e9
+	} else if sum.Seconds < 0 && sum.Nanos > 0 {
+		sum.Seconds++
+		sum.Nanos -= 1e9
+	}
+	return sum
+}
+
+// ToGoDuration converts d to a time.Duration. It returns an error if d is
+// invalid or too large to be represented by a time.Duration (whose range
+// is about 290 years, versus Duration's roughly 10,000 years).
+func (d *Duration) ToGoDuration() (time.Duration, error) {
+	return DurationFromProto(d)
+}
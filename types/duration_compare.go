@@ -0,0 +1,16 @@
+package types
+
+// This file adds a validity check on top of google.protobuf.Duration.
+//
+// Note: String() string is already defined in duration_gogo.go (rendering
+// the Go time.Duration form, e.g. "1h2m3s"), and Compare(that interface{})
+// int is already generated in duration.pb.go by the gogoproto compare
+// plugin, so neither is redeclared here.
+
+// IsValid reports whether d is a well-formed Duration: its Seconds field is
+// within the representable range (roughly +/-10,000 years), its Nanos field
+// is in (-1e9, 1e9), and Seconds and Nanos have the same sign (or one of
+// them is zero). A nil d is not valid.
+func (d *Duration) IsValid() bool {
+	return d != nil && validateDuration(d) == nil
+}
@@ -0,0 +1,41 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestDurationIsValid(t *testing.T) {
+	valid := &types.Duration{Seconds: 5, Nanos: 500}
+	if !valid.IsValid() {
+		t.Error("IsValid() = false for well-formed Duration, want true")
+	}
+
+	badNanos := &types.Duration{Seconds: 0, Nanos: 1e9}
+	if badNanos.IsValid() {
+		t.Error("IsValid() = true for out-of-range nanos, want false")
+	}
+
+	mismatchedSign := &types.Duration{Seconds: 1, Nanos: -1}
+	if mismatchedSign.IsValid() {
+		t.Error("IsValid() = true for mismatched signs, want false")
+	}
+
+	var nilD *types.Duration
+	if nilD.IsValid() {
+		t.Error("IsValid() = true for nil Duration, want false")
+	}
+}
+
+func TestDurationStringAndCompare(t *testing.T) {
+	d1 := &types.Duration{Seconds: 90}
+	if got, want := d1.String(), "1m30s"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	d2 := &types.Duration{Seconds: 90}
+	if c := d1.Compare(d2); c != 0 {
+		t.Errorf("Compare(equal) = %d, want 0", c)
+	}
+}
@@ -118,3 +118,18 @@ func TestDurationProto(t *testing.T) {
 		}
 	}
 }
+
+func TestDurationAdd(t *testing.T) {
+	sum := DurationFromGo(600 * time.Millisecond).Add(DurationFromGo(700 * time.Millisecond))
+	want := DurationFromGo(1300 * time.Millisecond)
+	if !proto.Equal(sum, want) {
+		t.Errorf("Add across second boundary = %v, want %v", sum, want)
+	}
+}
+
+func TestDurationToGoDurationOverflow(t *testing.T) {
+	d := &Duration{Seconds: maxGoSeconds + 1}
+	if _, err := d.ToGoDuration(); err == nil {
+		t.Errorf("ToGoDuration(%v) succeeded, want overflow error", d)
+	}
+}
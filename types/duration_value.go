@@ -0,0 +1,25 @@
+package types
+
+// This file implements Value constructors for time.Duration, complementing
+// NewValue's built-in handling of it.
+
+import "time"
+
+// NewDurationValue constructs a new StringValue holding d formatted the same
+// way time.Duration.String does, e.g. "1.5s". This is the representation
+// NewValue uses for a time.Duration, matching the proto-JSON convention of
+// representing a Duration as a unit-suffixed string rather than a bare
+// number of seconds or nanoseconds, which would be ambiguous without a
+// schema to consult.
+func NewDurationValue(d time.Duration) *Value {
+	return NewStringValue(d.String())
+}
+
+// NewDurationValueNanos constructs a new NumberValue holding d's length in
+// nanoseconds. Use this instead of NewDurationValue when the consumer
+// expects a bare number rather than a unit-suffixed string; as with any
+// NumberValue, very large durations are subject to float64's precision
+// limits.
+func NewDurationValueNanos(d time.Duration) *Value {
+	return NewNumberValue(float64(d.Nanoseconds()))
+}
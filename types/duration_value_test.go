@@ -0,0 +1,33 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestNewValueDurationDefaultsToString(t *testing.T) {
+	d := 1500 * time.Millisecond
+
+	v, err := types.NewValue(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := v.GetStringValue(), "1.5s"; got != want {
+		t.Errorf("NewValue(%v).GetStringValue() = %q, want %q", d, got, want)
+	}
+
+	if got, want := types.NewDurationValue(d).GetStringValue(), "1.5s"; got != want {
+		t.Errorf("NewDurationValue(%v).GetStringValue() = %q, want %q", d, got, want)
+	}
+}
+
+func TestNewDurationValueNanos(t *testing.T) {
+	d := 1500 * time.Millisecond
+
+	v := types.NewDurationValueNanos(d)
+	if got, want := v.GetNumberValue(), float64(d.Nanoseconds()); got != want {
+		t.Errorf("NewDurationValueNanos(%v).GetNumberValue() = %v, want %v", d, got, want)
+	}
+}
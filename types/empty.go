@@ -0,0 +1,24 @@
+package types
+
+// This file implements JSON marshaling for google.protobuf.Empty.
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON renders x as the empty JSON object "{}", per the proto3 JSON
+// mapping for google.protobuf.Empty.
+func (x Empty) MarshalJSON() ([]byte, error) {
+	return []byte("{}"), nil
+}
+
+// UnmarshalJSON accepts any JSON object (its fields are ignored, since
+// Empty has none) or JSON null; any other JSON value is rejected.
+func (x *Empty) UnmarshalJSON(b []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("bad Empty: %v", err)
+	}
+	return nil
+}
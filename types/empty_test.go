@@ -0,0 +1,30 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEmptyMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(&Empty{})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(b) != "{}" {
+		t.Errorf("Marshal(&Empty{}) = %s, want {}", b)
+	}
+}
+
+func TestEmptyUnmarshalJSON(t *testing.T) {
+	for _, data := range []string{"{}", "null", `{"ignored":1}`} {
+		var e Empty
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			t.Errorf("Unmarshal(%s): %v", data, err)
+		}
+	}
+
+	var e Empty
+	if err := json.Unmarshal([]byte(`5`), &e); err == nil {
+		t.Error("Unmarshal(5) succeeded, want error")
+	}
+}
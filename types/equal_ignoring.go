@@ -0,0 +1,69 @@
+package types
+
+import "fmt"
+
+// EqualIgnoring reports whether x and y are equal, skipping any field whose
+// dotted path (using the same "parent.key" / "parent[i]" convention as
+// Value.Walk) appears in ignorePaths. This is intended for test assertions
+// that need to ignore volatile fields, such as timestamps or generated IDs,
+// without stripping them from either Struct first.
+func (x *Struct) EqualIgnoring(y *Struct, ignorePaths []string) bool {
+	ignore := make(map[string]bool, len(ignorePaths))
+	for _, p := range ignorePaths {
+		ignore[p] = true
+	}
+	return structEqualIgnoring(x, y, "", ignore)
+}
+
+func structEqualIgnoring(x, y *Struct, path string, ignore map[string]bool) bool {
+	xf, yf := x.GetFields(), y.GetFields()
+	seen := make(map[string]bool, len(xf)+len(yf))
+	for k := range xf {
+		seen[k] = true
+	}
+	for k := range yf {
+		seen[k] = true
+	}
+	for k := range seen {
+		fieldPath := joinPath(path, k)
+		if ignore[fieldPath] {
+			continue
+		}
+		xv, xok := xf[k]
+		yv, yok := yf[k]
+		if xok != yok {
+			return false
+		}
+		if !valueEqualIgnoring(xv, yv, fieldPath, ignore) {
+			return false
+		}
+	}
+	return true
+}
+
+func valueEqualIgnoring(x, y *Value, path string, ignore map[string]bool) bool {
+	if xs, ok := x.GetKind().(*Value_StructValue); ok {
+		if ys, ok := y.GetKind().(*Value_StructValue); ok {
+			return structEqualIgnoring(xs.StructValue, ys.StructValue, path, ignore)
+		}
+	}
+	if xl, ok := x.GetKind().(*Value_ListValue); ok {
+		if yl, ok := y.GetKind().(*Value_ListValue); ok {
+			xv, yv := xl.ListValue.GetValues(), yl.ListValue.GetValues()
+			if len(xv) != len(yv) {
+				return false
+			}
+			for i := range xv {
+				elemPath := fmt.Sprintf("%s[%d]", path, i)
+				if ignore[elemPath] {
+					continue
+				}
+				if !valueEqualIgnoring(xv[i], yv[i], elemPath, ignore) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return x.Equal(y)
+}
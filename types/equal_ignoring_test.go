@@ -0,0 +1,52 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructEqualIgnoring(t *testing.T) {
+	a, err := types.NewStruct(map[string]interface{}{
+		"name":      "widget",
+		"createdAt": "2026-08-01T00:00:00Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := types.NewStruct(map[string]interface{}{
+		"name":      "widget",
+		"createdAt": "2026-08-09T00:00:00Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Equal(b) {
+		t.Fatal("a.Equal(b) = true, want false (createdAt differs)")
+	}
+	if !a.EqualIgnoring(b, []string{"createdAt"}) {
+		t.Error("a.EqualIgnoring(b, [createdAt]) = false, want true")
+	}
+	if a.EqualIgnoring(b, nil) {
+		t.Error("a.EqualIgnoring(b, nil) = true, want false")
+	}
+}
+
+func TestStructEqualIgnoringNestedPath(t *testing.T) {
+	a, err := types.NewStruct(map[string]interface{}{
+		"meta": map[string]interface{}{"id": "abc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := types.NewStruct(map[string]interface{}{
+		"meta": map[string]interface{}{"id": "xyz"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.EqualIgnoring(b, []string{"meta.id"}) {
+		t.Error("a.EqualIgnoring(b, [meta.id]) = false, want true")
+	}
+}
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandEnv returns a deep copy of x with every "${NAME}" occurrence in
+// every StringValue replaced by lookup(NAME). A placeholder lookup cannot
+// resolve is left as literal text; use ExpandEnvStrict to error instead.
+func (x *Struct) ExpandEnv(lookup func(string) (string, bool)) *Struct {
+	out, _ := expandEnvStruct(x, lookup, false)
+	return out
+}
+
+// ExpandOSEnv is a convenience wrapper around ExpandEnv using os.LookupEnv.
+func (x *Struct) ExpandOSEnv() *Struct {
+	return x.ExpandEnv(os.LookupEnv)
+}
+
+// ExpandEnvStrict is like ExpandEnv, but returns an error naming the first
+// "${NAME}" placeholder that lookup cannot resolve, instead of leaving it
+// as literal text.
+func (x *Struct) ExpandEnvStrict(lookup func(string) (string, bool)) (*Struct, error) {
+	return expandEnvStruct(x, lookup, true)
+}
+
+func expandEnvStruct(x *Struct, lookup func(string) (string, bool), errorOnUnresolved bool) (*Struct, error) {
+	fields := x.GetFields()
+	out := &Struct{Fields: make(map[string]*Value, len(fields))}
+	for k, v := range fields {
+		cv, err := expandEnvValue(v, lookup, errorOnUnresolved)
+		if err != nil {
+			return nil, err
+		}
+		out.Fields[k] = cv
+	}
+	return out, nil
+}
+
+func expandEnvValue(v *Value, lookup func(string) (string, bool), errorOnUnresolved bool) (*Value, error) {
+	switch k := v.GetKind().(type) {
+	case *Value_StringValue:
+		expanded, err := expandEnvString(k.StringValue, lookup, errorOnUnresolved)
+		if err != nil {
+			return nil, err
+		}
+		return NewStringValue(expanded), nil
+	case *Value_StructValue:
+		s, err := expandEnvStruct(k.StructValue, lookup, errorOnUnresolved)
+		if err != nil {
+			return nil, err
+		}
+		return NewStructValue(s), nil
+	case *Value_ListValue:
+		values := k.ListValue.GetValues()
+		out := make([]*Value, len(values))
+		for i, child := range values {
+			cv, err := expandEnvValue(child, lookup, errorOnUnresolved)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = cv
+		}
+		return NewListValue(&ListValue{Values: out}), nil
+	default:
+		return cloneValue(v), nil
+	}
+}
+
+func expandEnvString(s string, lookup func(string) (string, bool), errorOnUnresolved bool) (string, error) {
+	var firstErr error
+	result := envVarPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if firstErr != nil {
+			return match
+		}
+		name := match[2 : len(match)-1]
+		val, ok := lookup(name)
+		if !ok {
+			if errorOnUnresolved {
+				firstErr = fmt.Errorf("ExpandEnv: unresolved variable %q", name)
+			}
+			return match
+		}
+		return val
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func lookupFrom(m map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := m[name]
+		return v, ok
+	}
+}
+
+func TestExpandEnvResolved(t *testing.T) {
+	x, err := types.NewStruct(map[string]interface{}{
+		"url": "https://${HOST}:${PORT}/path",
+		"nested": map[string]interface{}{
+			"name": "${USER}",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lookup := lookupFrom(map[string]string{"HOST": "example.com", "PORT": "8080", "USER": "ada"})
+	got := x.ExpandEnv(lookup)
+
+	if want := "https://example.com:8080/path"; got.Fields["url"].AsInterface() != want {
+		t.Errorf("url = %v, want %v", got.Fields["url"].AsInterface(), want)
+	}
+	if want := "ada"; got.Fields["nested"].GetStructValue().Fields["name"].AsInterface() != want {
+		t.Errorf("nested.name = %v, want %v", got.Fields["nested"].GetStructValue().Fields["name"].AsInterface(), want)
+	}
+}
+
+func TestExpandEnvUnresolvedStaysLiteral(t *testing.T) {
+	x, err := types.NewStruct(map[string]interface{}{"v": "${MISSING}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := x.ExpandEnv(lookupFrom(nil))
+	if want := "${MISSING}"; got.Fields["v"].AsInterface() != want {
+		t.Errorf("v = %v, want %v", got.Fields["v"].AsInterface(), want)
+	}
+}
+
+func TestExpandEnvStrictErrorsOnUnresolved(t *testing.T) {
+	x, err := types.NewStruct(map[string]interface{}{"v": "${MISSING}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := x.ExpandEnvStrict(lookupFrom(nil)); err == nil {
+		t.Error("got nil error for unresolved variable, want error")
+	}
+}
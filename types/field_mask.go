@@ -0,0 +1,115 @@
+package types
+
+// This file implements operations on google.protobuf.FieldMask.
+
+import (
+	"sort"
+	"strings"
+)
+
+// Normalize rewrites m.Paths into canonical form: sorted, with duplicate
+// paths removed, and with any path dropped that is already covered by an
+// ancestor path also present in the mask (for example "a" subsumes "a.b").
+// This matches the canonical form described by the AIP field mask
+// guidelines and makes two masks comparable for equality after
+// normalizing both.
+func (m *FieldMask) Normalize() {
+	paths := append([]string(nil), m.GetPaths()...)
+	sort.Strings(paths)
+
+	out := paths[:0]
+	for _, p := range paths {
+		if len(out) > 0 && (out[len(out)-1] == p || isAncestorPath(out[len(out)-1], p)) {
+			continue
+		}
+		out = append(out, p)
+	}
+	m.Paths = out
+}
+
+// isAncestorPath reports whether ancestor is a strict ancestor of path,
+// i.e. path is ancestor itself followed by ".".
+func isAncestorPath(ancestor, path string) bool {
+	return strings.HasPrefix(path, ancestor+".")
+}
+
+// ApplyFieldMask returns a new Struct containing only the fields named by
+// m's paths. Dotted paths (e.g. "a.b") select a nested subtree; selecting
+// a path whose parent is not a Struct, or that does not exist in x, is a
+// no-op for that path.
+func (x *Struct) ApplyFieldMask(m *FieldMask) *Struct {
+	out := &Struct{Fields: make(map[string]*Value)}
+	for _, path := range m.GetPaths() {
+		if v, ok := lookupPath(x, strings.Split(path, ".")); ok {
+			setPath(out, strings.Split(path, "."), v)
+		}
+	}
+	return out
+}
+
+// MergeWithMask overwrites, in place on x, only the fields of x named by
+// m's paths with the corresponding values from src. Dotted paths select a
+// nested subtree; a path missing from src clears the corresponding field
+// in x.
+func (x *Struct) MergeWithMask(src *Struct, m *FieldMask) {
+	if x.Fields == nil {
+		x.Fields = make(map[string]*Value)
+	}
+	for _, path := range m.GetPaths() {
+		if v, ok := lookupPath(src, strings.Split(path, ".")); ok {
+			setPath(x, strings.Split(path, "."), v)
+		} else {
+			deletePath(x, strings.Split(path, "."))
+		}
+	}
+}
+
+// lookupPath follows segs through nested Structs starting at x, returning
+// the Value at that path and whether it was found.
+func lookupPath(x *Struct, segs []string) (*Value, bool) {
+	v, ok := x.GetFields()[segs[0]]
+	if !ok {
+		return nil, false
+	}
+	if len(segs) == 1 {
+		return v, true
+	}
+	sv, ok := v.GetKind().(*Value_StructValue)
+	if !ok {
+		return nil, false
+	}
+	return lookupPath(sv.StructValue, segs[1:])
+}
+
+// setPath assigns v at the nested path segs within x, creating
+// intermediate Structs as needed.
+func setPath(x *Struct, segs []string, v *Value) {
+	if x.Fields == nil {
+		x.Fields = make(map[string]*Value)
+	}
+	if len(segs) == 1 {
+		x.Fields[segs[0]] = v
+		return
+	}
+	child, ok := x.Fields[segs[0]].GetKind().(*Value_StructValue)
+	if !ok {
+		s := &Struct{Fields: make(map[string]*Value)}
+		x.Fields[segs[0]] = NewStructValue(s)
+		child = &Value_StructValue{StructValue: s}
+	}
+	setPath(child.StructValue, segs[1:], v)
+}
+
+// deletePath removes the field at the nested path segs within x, if
+// present.
+func deletePath(x *Struct, segs []string) {
+	if len(segs) == 1 {
+		delete(x.Fields, segs[0])
+		return
+	}
+	sv, ok := x.Fields[segs[0]].GetKind().(*Value_StructValue)
+	if !ok {
+		return
+	}
+	deletePath(sv.StructValue, segs[1:])
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// MarshalJSON renders m as the comma-joined, camelCase-converted string
+// form mandated by the FieldMask JSON mapping, e.g. paths
+// ["foo_bar.baz"] marshal as "fooBar.baz". Field accessors keep returning
+// the snake_case proto paths (via GetPaths, since a Paths() method would
+// collide with the Paths field); only the wire JSON form is camelCase.
+func (m *FieldMask) MarshalJSON() ([]byte, error) {
+	paths := m.GetPaths()
+	segs := make([]string, len(paths))
+	for i, p := range paths {
+		segs[i] = camelCasePath(p)
+	}
+	return json.Marshal(strings.Join(segs, ","))
+}
+
+// UnmarshalJSON parses the comma-joined camelCase string form produced by
+// MarshalJSON, converting each path back to snake_case.
+func (m *FieldMask) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		m.Paths = nil
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	paths := make([]string, len(parts))
+	for i, p := range parts {
+		paths[i] = snakeCasePath(p)
+	}
+	m.Paths = paths
+	return nil
+}
+
+// camelCasePath converts every "."-separated segment of a snake_case field
+// path to camelCase, per the proto3 JSON name mapping: each underscore is
+// dropped and the following letter capitalized.
+func camelCasePath(path string) string {
+	segs := strings.Split(path, ".")
+	for i, seg := range segs {
+		segs[i] = snakeToCamel(seg)
+	}
+	return strings.Join(segs, ".")
+}
+
+// snakeCasePath is the inverse of camelCasePath.
+func snakeCasePath(path string) string {
+	segs := strings.Split(path, ".")
+	for i, seg := range segs {
+		segs[i] = camelToSnake(seg)
+	}
+	return strings.Join(segs, ".")
+}
+
+func snakeToCamel(s string) string {
+	var b strings.Builder
+	upperNext := false
+	for _, r := range s {
+		if r == '_' {
+			upperNext = true
+			continue
+		}
+		if upperNext && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upperNext = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func camelToSnake(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+			b.WriteRune(r - 'A' + 'a')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestFieldMaskJSONCamelCaseRoundTrip(t *testing.T) {
+	m := &types.FieldMask{Paths: []string{"foo_bar.baz", "id"}}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"fooBar.baz,id"`; string(b) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", b, want)
+	}
+
+	var got types.FieldMask
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Paths) != 2 || got.Paths[0] != "foo_bar.baz" || got.Paths[1] != "id" {
+		t.Errorf("round-tripped Paths = %v, want [foo_bar.baz id]", got.Paths)
+	}
+}
+
+func TestFieldMaskGetPathsReturnsSnakeCase(t *testing.T) {
+	m := &types.FieldMask{Paths: []string{"foo_bar.baz"}}
+	if got := m.GetPaths(); len(got) != 1 || got[0] != "foo_bar.baz" {
+		t.Errorf("GetPaths() = %v, want [foo_bar.baz]", got)
+	}
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStructApplyFieldMask(t *testing.T) {
+	x, err := NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{"b": 1.0, "c": 2.0},
+		"d": "keep-out",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := x.ApplyFieldMask(&FieldMask{Paths: []string{"a.b"}})
+	want, err := NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{"b": 1.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.AsMap(), want.AsMap()) {
+		t.Errorf("ApplyFieldMask = %v, want %v", got.AsMap(), want.AsMap())
+	}
+}
+
+func TestStructMergeWithMask(t *testing.T) {
+	dst, err := NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{"b": 1.0, "c": 2.0},
+		"d": "unchanged",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{"b": 99.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst.MergeWithMask(src, &FieldMask{Paths: []string{"a.b"}})
+
+	want, err := NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{"b": 99.0, "c": 2.0},
+		"d": "unchanged",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dst.AsMap(), want.AsMap()) {
+		t.Errorf("MergeWithMask = %v, want %v", dst.AsMap(), want.AsMap())
+	}
+}
+
+func TestFieldMaskNormalize(t *testing.T) {
+	tests := []struct {
+		desc  string
+		paths []string
+		want  []string
+	}{
+		{"duplicates", []string{"a.b", "a.b", "c"}, []string{"a.b", "c"}},
+		{"ancestor subsumes child", []string{"a.b", "a", "c"}, []string{"a", "c"}},
+		{"ancestor subsumes grandchild", []string{"a.b.c", "a"}, []string{"a"}},
+		{"siblings kept", []string{"a.c", "a.b"}, []string{"a.b", "a.c"}},
+		{"prefix but not ancestor kept", []string{"ab", "a"}, []string{"a", "ab"}},
+	}
+	for _, tt := range tests {
+		m := &FieldMask{Paths: tt.paths}
+		m.Normalize()
+		if !reflect.DeepEqual(m.Paths, tt.want) {
+			t.Errorf("%s: Normalize(%v) = %v, want %v", tt.desc, tt.paths, m.Paths, tt.want)
+		}
+	}
+}
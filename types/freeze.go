@@ -0,0 +1,49 @@
+package types
+
+// ReadOnlyStruct is an immutable view onto a Struct: it exposes only
+// read methods, with no way to reach the backing Fields map and mutate it.
+// Obtain one with Struct.Freeze.
+type ReadOnlyStruct struct {
+	s *Struct
+}
+
+// Freeze returns a ReadOnlyStruct view onto x, for passing a shared config
+// Struct to code that should only read it. The view aliases x: later
+// mutation of x through other references is visible through the view,
+// since ReadOnlyStruct only restricts what its own holder can do, not
+// what happens to the underlying data from elsewhere.
+func (x *Struct) Freeze() ReadOnlyStruct {
+	return ReadOnlyStruct{s: x}
+}
+
+// Get returns the field key and whether it is present.
+func (r ReadOnlyStruct) Get(key string) (*Value, bool) {
+	v, ok := r.s.GetFields()[key]
+	return v, ok
+}
+
+// Has reports whether key is present.
+func (r ReadOnlyStruct) Has(key string) bool {
+	return r.s.Has(key)
+}
+
+// Range calls f for each field in unspecified order, stopping early if f
+// returns false.
+func (r ReadOnlyStruct) Range(f func(key string, v *Value) bool) {
+	for k, v := range r.s.GetFields() {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+// AsMap converts the underlying Struct to a general-purpose Go map, the
+// same as Struct.AsMap.
+func (r ReadOnlyStruct) AsMap() map[string]interface{} {
+	return r.s.AsMap()
+}
+
+// Len returns the number of fields.
+func (r ReadOnlyStruct) Len() int {
+	return r.s.Len()
+}
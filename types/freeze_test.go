@@ -0,0 +1,55 @@
+package types_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructFreezeReflectsSourceAndIsReadOnly(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{"a": 1.0, "b": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ro := s.Freeze()
+	if got, ok := ro.Get("a"); !ok || got.GetNumberValue() != 1 {
+		t.Errorf("Get(a) = %v, %v, want 1, true", got, ok)
+	}
+	if !ro.Has("b") {
+		t.Errorf("Has(b) = false, want true")
+	}
+	if got := ro.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+
+	seen := map[string]bool{}
+	ro.Range(func(key string, v *types.Value) bool {
+		seen[key] = true
+		return true
+	})
+	if !seen["a"] || !seen["b"] {
+		t.Errorf("Range() saw %v, want a and b", seen)
+	}
+
+	// Mutating the source after Freeze is visible through the view: Freeze
+	// restricts what the holder of the ReadOnlyStruct can do, not whether
+	// the underlying Struct itself can still change.
+	s.Fields["c"] = types.NewBoolValue(true)
+	if !ro.Has("c") {
+		t.Errorf("Has(c) = false after source mutation, want true")
+	}
+}
+
+func TestReadOnlyStructHasNoMutators(t *testing.T) {
+	// ReadOnlyStruct intentionally has no Set/Delete methods; this test
+	// documents that expectation so a future change adding one is a
+	// deliberate decision, not an oversight.
+	var ro types.ReadOnlyStruct
+	for _, name := range []string{"Set", "Delete"} {
+		if _, ok := reflect.TypeOf(&ro).MethodByName(name); ok {
+			t.Errorf("ReadOnlyStruct has a %s method, want none", name)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// FuzzValueUnmarshalRoundTrip feeds arbitrary bytes into Value.UnmarshalJSON
+// and, for every input that decodes successfully, re-marshals and
+// re-unmarshals the result, asserting the two decoded Values are Equal.
+// This guards the core JSON codec (numbers, string escapes, nesting)
+// against round-trip regressions.
+func FuzzValueUnmarshalRoundTrip(f *testing.F) {
+	seeds := []string{
+		`null`,
+		`true`,
+		`false`,
+		`0`,
+		`-0`,
+		`3.5`,
+		`1e10`,
+		`1e-10`,
+		`-1.5e+300`,
+		`9223372036854775807`,
+		`""`,
+		`"hello"`,
+		`"with \"escapes\" and A and \n\t"`,
+		`"😀"`,
+		`[]`,
+		`[1,2,3]`,
+		`[null,true,"x",[1,2],{"a":1}]`,
+		`{}`,
+		`{"a":1,"b":[1,2,3],"c":{"d":null}}`,
+		`NAN`,
+		`NaN`,
+		`Infinity`,
+		`-Infinity`,
+		`+1`,
+		`01`,
+		`1.`,
+		`.1`,
+		`1e`,
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		var v1 types.Value
+		if err := v1.UnmarshalJSON([]byte(s)); err != nil {
+			t.Skip()
+		}
+
+		b, err := v1.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON() after successful UnmarshalJSON(%q) error: %v", s, err)
+		}
+
+		var v2 types.Value
+		if err := v2.UnmarshalJSON(b); err != nil {
+			t.Fatalf("UnmarshalJSON(%s) (re-marshaled from %q) error: %v", b, s, err)
+		}
+
+		if !v1.Equal(&v2) {
+			t.Fatalf("round trip mismatch: UnmarshalJSON(%q) = %v, re-encoded as %s, re-decoded as %v", s, &v1, b, &v2)
+		}
+	})
+}
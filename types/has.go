@@ -0,0 +1,26 @@
+package types
+
+import "strings"
+
+// Has reports whether x has a top-level field named key. Has reports true
+// for any present field, even one that holds a NullValue; use HasPath if
+// the null-vs-absent distinction
+// matters. It is nil-safe.
+func (x *Struct) Has(key string) bool {
+	_, ok := x.GetFields()[key]
+	return ok
+}
+
+// HasPath reports whether the dotted path is present within x and not
+// null, descending through nested Structs the same way ApplyFieldMask
+// does. A path that is present but holds a NullValue reports false, since
+// HasPath is meant to answer "does this path exist and is non-null?"; use
+// Has for a top-level, null-tolerant check. It is nil-safe.
+func (x *Struct) HasPath(path string) bool {
+	v, ok := lookupPath(x, strings.Split(path, "."))
+	if !ok {
+		return false
+	}
+	_, isNull := v.GetKind().(*Value_NullValue)
+	return !isNull
+}
@@ -0,0 +1,52 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructHas(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"present": 1.0,
+		"null":    nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !s.Has("present") {
+		t.Error("Has(\"present\") = false, want true")
+	}
+	if !s.Has("null") {
+		t.Error("Has(\"null\") = false, want true (present, even though null)")
+	}
+	if s.Has("absent") {
+		t.Error("Has(\"absent\") = true, want false")
+	}
+}
+
+func TestStructHasPath(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": 1.0,
+			"c": nil,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !s.HasPath("a.b") {
+		t.Error("HasPath(\"a.b\") = false, want true")
+	}
+	if s.HasPath("a.c") {
+		t.Error("HasPath(\"a.c\") = true, want false (present but null)")
+	}
+	if s.HasPath("a.missing") {
+		t.Error("HasPath(\"a.missing\") = true, want false")
+	}
+	if s.HasPath("missing.path") {
+		t.Error("HasPath(\"missing.path\") = true, want false")
+	}
+}
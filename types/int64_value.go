@@ -0,0 +1,21 @@
+package types
+
+import "strconv"
+
+// NewInt64Value returns a Value storing v as its decimal string
+// representation, rather than coercing it through float64 the way
+// NewValue(int64) does. This avoids losing precision for IDs and other
+// integers that don't fit exactly into a float64's 53-bit mantissa. It is
+// an ordinary StringValue, so it marshals, copies, and displays like any
+// other Value; decode it back with AsInt64 rather than expecting a bare
+// JSON number, matching how jsonpb itself renders int64/uint64 scalar
+// fields as quoted strings for the same reason.
+func NewInt64Value(v int64) *Value {
+	return NewStringValue(strconv.FormatInt(v, 10))
+}
+
+// NewUint64Value is NewInt64Value for uint64, decoded back out with
+// AsUint64.
+func NewUint64Value(v uint64) *Value {
+	return NewStringValue(strconv.FormatUint(v, 10))
+}
@@ -0,0 +1,76 @@
+package types_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestNewInt64ValueSurvivesMaxInt64(t *testing.T) {
+	v := types.NewInt64Value(math.MaxInt64)
+	got, err := v.AsInt64()
+	if err != nil {
+		t.Fatalf("AsInt64() error = %v, want nil", err)
+	}
+	if got != math.MaxInt64 {
+		t.Errorf("AsInt64() = %d, want %d", got, int64(math.MaxInt64))
+	}
+	if _, ok := v.GetKind().(*types.Value_StringValue); !ok {
+		t.Errorf("got %v, want StringValue (NewValue(int64) would lose precision here)", v)
+	}
+}
+
+func TestNewUint64ValueSurvivesMaxUint64(t *testing.T) {
+	var max uint64 = math.MaxUint64
+	v := types.NewUint64Value(max)
+	got, err := v.AsUint64()
+	if err != nil {
+		t.Fatalf("AsUint64() error = %v, want nil", err)
+	}
+	if got != max {
+		t.Errorf("AsUint64() = %d, want %d", got, max)
+	}
+}
+
+func TestAsInt64FallsBackToNumberValueWithinExactRange(t *testing.T) {
+	got, err := types.NewNumberValue(42).AsInt64()
+	if err != nil {
+		t.Fatalf("AsInt64() error = %v, want nil", err)
+	}
+	if got != 42 {
+		t.Errorf("AsInt64() = %d, want 42", got)
+	}
+}
+
+func TestAsInt64RejectsNonNumericStringValue(t *testing.T) {
+	if _, err := types.NewStringValue("not a number").AsInt64(); err == nil {
+		t.Error("AsInt64() on a non-numeric StringValue: error = nil, want error")
+	}
+}
+
+// TestNewInt64ValueIsPlainStringThroughGeneralPurposeAccessors guards against
+// reintroducing the internal numericTagPrefix scheme (used by
+// MarshalPreservingNumbers) here: NewInt64Value/NewUint64Value must produce
+// an ordinary Value that every general-purpose accessor, not just AsInt64/
+// AsUint64, renders as a plain decimal string.
+func TestNewInt64ValueIsPlainStringThroughGeneralPurposeAccessors(t *testing.T) {
+	v := types.NewInt64Value(42)
+	if got, want := v.AsInterface(), interface{}("42"); got != want {
+		t.Errorf("AsInterface() = %q, want %q", got, want)
+	}
+
+	s := &types.Struct{Fields: map[string]*types.Value{"id": v}}
+	m := s.AsMap()
+	if got, want := m["id"], interface{}("42"); got != want {
+		t.Errorf("AsMap()[\"id\"] = %q, want %q", got, want)
+	}
+
+	b, err := s.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	if got, want := string(b), `{"id":"42"}`; got != want {
+		t.Errorf("MarshalJSON() = %s, want %s", got, want)
+	}
+}
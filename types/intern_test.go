@@ -0,0 +1,75 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestDecoderInternStringsDeduplicates(t *testing.T) {
+	data := []byte(`[{"level":"info","msg":"ok"},{"level":"info","msg":"ok"}]`)
+
+	var lv types.ListValue
+	d := types.NewDecoder()
+	d.Options.InternStrings = true
+	if err := d.Decode(data, &lv); err != nil {
+		t.Fatal(err)
+	}
+
+	first := lv.Values[0].GetStructValue()
+	second := lv.Values[1].GetStructValue()
+
+	if got, want := first.Fields["level"].GetStringValue(), "info"; got != want {
+		t.Errorf("level = %q, want %q", got, want)
+	}
+	if got, want := second.Fields["msg"].GetStringValue(), "ok"; got != want {
+		t.Errorf("msg = %q, want %q", got, want)
+	}
+	if got, want := second.Fields["level"].GetStringValue(), "info"; got != want {
+		t.Errorf("level = %q, want %q", got, want)
+	}
+}
+
+func structForInternBenchmark() []byte {
+	entries := make([]map[string]interface{}, 0, 500)
+	for i := 0; i < 500; i++ {
+		entries = append(entries, map[string]interface{}{
+			"level":   "info",
+			"service": "checkout",
+			"message": "request completed successfully",
+		})
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func BenchmarkUnmarshalListValueInternStrings(b *testing.B) {
+	data := structForInternBenchmark()
+	d := types.NewDecoder()
+	d.Options.InternStrings = true
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d.Reset()
+		var lv types.ListValue
+		if err := d.Decode(data, &lv); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalListValueNoIntern(b *testing.B) {
+	data := structForInternBenchmark()
+	d := types.NewDecoder()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d.Reset()
+		var lv types.ListValue
+		if err := d.Decode(data, &lv); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
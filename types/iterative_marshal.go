@@ -0,0 +1,142 @@
+package types
+
+// This file implements an iterative JSON marshaler for Value trees. The
+// MarshalJSON methods on Value, Struct, and ListValue recurse through
+// "encoding/json" to marshal nested containers, so a Value tree built
+// programmatically to an adversarial depth can overflow the goroutine
+// stack. MarshalIterative walks the tree with an explicit stack instead,
+// bounded by a configurable depth limit that turns "too deep" into an
+// error rather than a crash.
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// IterativeMarshalOptions configures MarshalIterative.
+type IterativeMarshalOptions struct {
+	// MaxDepth caps the depth of Struct/ListValue nesting that will be
+	// marshaled. Exceeding it returns an error instead of recursing
+	// further. Zero means use DefaultMaxMarshalDepth.
+	MaxDepth int
+
+	// KeyOrder, when set, is called with a Struct's keys (already sorted
+	// lexicographically) before they are emitted, and its returned slice
+	// is used as the emission order instead, same as
+	// MarshalOptions.KeyOrder.
+	KeyOrder func(keys []string) []string
+}
+
+// DefaultMaxMarshalDepth is the depth limit MarshalIterative uses when
+// IterativeMarshalOptions.MaxDepth is zero.
+const DefaultMaxMarshalDepth = 10000
+
+// MarshalIterative encodes x to JSON the same way x.MarshalJSON does, but
+// without recursing through nested Structs and ListValues, so it can't
+// overflow the stack on an adversarially deep tree. It returns an error if
+// x is nested deeper than DefaultMaxMarshalDepth.
+func MarshalIterative(x *Value) ([]byte, error) {
+	return IterativeMarshalOptions{}.Marshal(x)
+}
+
+// Marshal encodes x to JSON according to o, iteratively.
+func (o IterativeMarshalOptions) Marshal(x *Value) ([]byte, error) {
+	maxDepth := o.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxMarshalDepth
+	}
+
+	var buf bytes.Buffer
+
+	// Each frame represents a container (object or list) currently being
+	// written, and how far through its children we've gotten.
+	type frame struct {
+		isObj  bool
+		keys   []string
+		fields map[string]*Value
+		values []*Value
+		idx    int
+		depth  int
+	}
+	var stack []*frame
+
+	open := func(v *Value, depth int) error {
+		if depth > maxDepth {
+			return fmt.Errorf("types: MarshalIterative: exceeded max depth %d", maxDepth)
+		}
+		switch k := v.GetKind().(type) {
+		case *Value_StructValue:
+			sv := k.StructValue
+			keys := sv.Keys()
+			if o.KeyOrder != nil {
+				keys = o.KeyOrder(keys)
+			}
+			buf.WriteByte('{')
+			if len(keys) == 0 {
+				buf.WriteByte('}')
+				return nil
+			}
+			stack = append(stack, &frame{isObj: true, keys: keys, fields: sv.GetFields(), depth: depth})
+		case *Value_ListValue:
+			values := k.ListValue.GetValues()
+			buf.WriteByte('[')
+			if len(values) == 0 {
+				buf.WriteByte(']')
+				return nil
+			}
+			stack = append(stack, &frame{isObj: false, values: values, depth: depth})
+		default:
+			// Scalars (including nil/NullValue) never recurse, so it's
+			// safe to defer to the ordinary Marshaler here.
+			b, err := v.MarshalJSON()
+			if err != nil {
+				return err
+			}
+			buf.Write(b)
+		}
+		return nil
+	}
+
+	if err := open(x, 0); err != nil {
+		return nil, err
+	}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		if top.isObj {
+			if top.idx >= len(top.keys) {
+				buf.WriteByte('}')
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			if top.idx > 0 {
+				buf.WriteByte(',')
+			}
+			key := top.keys[top.idx]
+			top.idx++
+			keyJSON, err := marshalJSONStringRaw(key)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(keyJSON)
+			buf.WriteByte(':')
+			if err := open(top.fields[key], top.depth+1); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if top.idx >= len(top.values) {
+			buf.WriteByte(']')
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if top.idx > 0 {
+			buf.WriteByte(',')
+		}
+		child := top.values[top.idx]
+		top.idx++
+		if err := open(child, top.depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
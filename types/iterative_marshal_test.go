@@ -0,0 +1,101 @@
+package types_test
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestMarshalIterativeMatchesRecursive(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"a": "x",
+		"b": []interface{}{1.0, 2.0, map[string]interface{}{"c": true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := types.NewStructValue(s)
+
+	want, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := types.MarshalIterative(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wantParsed, gotParsed types.Struct
+	if err := json.Unmarshal(want, &wantParsed); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(got, &gotParsed); err != nil {
+		t.Fatal(err)
+	}
+	if !wantParsed.Equal(&gotParsed) {
+		t.Errorf("MarshalIterative = %s, want (decoded-equivalent to) %s", got, want)
+	}
+}
+
+func TestIterativeMarshalOptionsKeyOrder(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"type": "x",
+		"id":   1.0,
+		"name": "y",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	front := map[string]int{"id": 0, "type": 1}
+	o := types.IterativeMarshalOptions{
+		KeyOrder: func(keys []string) []string {
+			out := append([]string(nil), keys...)
+			sort.SliceStable(out, func(i, j int) bool {
+				pi, iok := front[out[i]]
+				pj, jok := front[out[j]]
+				if iok && jok {
+					return pi < pj
+				}
+				return iok && !jok
+			})
+			return out
+		},
+	}
+	got, err := o.Marshal(types.NewStructValue(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id":1,"type":"x","name":"y"}`; string(got) != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestMarshalIterativeDeepListDoesNotPanic(t *testing.T) {
+	const depth = 100000
+	v := types.NewNumberValue(1)
+	for i := 0; i < depth; i++ {
+		v = types.NewListValue(&types.ListValue{Values: []*types.Value{v}})
+	}
+
+	got, err := (types.IterativeMarshalOptions{MaxDepth: depth + 1}).Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if n := strings.Count(string(got), "["); n != depth {
+		t.Errorf("got %d '[' characters, want %d", n, depth)
+	}
+}
+
+func TestMarshalIterativeRejectsExcessiveDepth(t *testing.T) {
+	v := types.NewListValue(&types.ListValue{Values: []*types.Value{
+		types.NewListValue(&types.ListValue{Values: []*types.Value{types.NewNumberValue(1)}}),
+	}})
+	_, err := (types.IterativeMarshalOptions{MaxDepth: 1}).Marshal(v)
+	if err == nil {
+		t.Fatal("got nil error, want depth-exceeded error")
+	}
+}
@@ -0,0 +1,35 @@
+package types
+
+// JSONString returns a concise JSON representation of x, for use in logs and
+// debugging where the generated String method's Go-syntax form (exposing the
+// internal Kind oneof wrapper) is hard to read. It falls back to "<invalid>"
+// if MarshalJSON fails, which should not normally happen for a validly
+// constructed Value.
+//
+// This is named JSONString rather than String because String is already
+// defined by the generated stringer in struct.pb.go.
+func (x *Value) JSONString() string {
+	b, err := x.MarshalJSON()
+	if err != nil {
+		return "<invalid>"
+	}
+	return string(b)
+}
+
+// JSONString returns a concise JSON representation of x; see Value.JSONString.
+func (x *Struct) JSONString() string {
+	b, err := x.MarshalJSON()
+	if err != nil {
+		return "<invalid>"
+	}
+	return string(b)
+}
+
+// JSONString returns a concise JSON representation of x; see Value.JSONString.
+func (x *ListValue) JSONString() string {
+	b, err := x.MarshalJSON()
+	if err != nil {
+		return "<invalid>"
+	}
+	return string(b)
+}
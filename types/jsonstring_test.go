@@ -0,0 +1,42 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestJSONStringMatchesMarshalJSON(t *testing.T) {
+	x, err := types.NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{"b": []interface{}{1.0, "two", true, nil}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := x.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := x.JSONString(); got != string(want) {
+		t.Errorf("Struct.JSONString() = %q, want %q", got, want)
+	}
+
+	nested := x.Fields["a"]
+	wantNested, err := nested.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := nested.JSONString(); got != string(wantNested) {
+		t.Errorf("Value.JSONString() = %q, want %q", got, wantNested)
+	}
+
+	lv := nested.GetStructValue().Fields["b"].GetListValue()
+	wantList, err := lv.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lv.JSONString(); got != string(wantList) {
+		t.Errorf("ListValue.JSONString() = %q, want %q", got, wantList)
+	}
+}
@@ -0,0 +1,89 @@
+package types
+
+// stripCommentsAndTrailingCommas rewrites data, which is assumed to be
+// JSON5-ish, into strict JSON by dropping "//" and "/* */" comments and any
+// trailing comma that precedes a closing "]" or "}". It is string-aware, so
+// none of this applies inside a quoted string. This backs
+// UnmarshalOptions.Lenient.
+func stripCommentsAndTrailingCommas(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	n := len(data)
+	for i := 0; i < n; {
+		c := data[i]
+		switch {
+		case c == '"':
+			start := i
+			i++
+			for i < n {
+				if data[i] == '\\' && i+1 < n {
+					i += 2
+					continue
+				}
+				if data[i] == '"' {
+					i++
+					break
+				}
+				i++
+			}
+			out = append(out, data[start:i]...)
+		case c == '/' && i+1 < n && data[i+1] == '/':
+			i += 2
+			for i < n && data[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < n && data[i+1] == '*':
+			i += 2
+			for i+1 < n && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i += 2
+			if i > n {
+				i = n
+			}
+		case c == ',':
+			if isTrailingComma(data, i+1) {
+				i++
+				for i < n && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+					i++
+				}
+				continue
+			}
+			out = append(out, c)
+			i++
+		default:
+			out = append(out, c)
+			i++
+		}
+	}
+	return out
+}
+
+// isTrailingComma reports whether the next significant (non-whitespace,
+// non-comment) byte starting at i is a closing "]" or "}", meaning the comma
+// before it is a JSON5-style trailing comma to be dropped.
+func isTrailingComma(data []byte, i int) bool {
+	n := len(data)
+	for i < n {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r':
+			i++
+		case data[i] == '/' && i+1 < n && data[i+1] == '/':
+			i += 2
+			for i < n && data[i] != '\n' {
+				i++
+			}
+		case data[i] == '/' && i+1 < n && data[i+1] == '*':
+			i += 2
+			for i+1 < n && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i += 2
+			if i > n {
+				i = n
+			}
+		default:
+			return data[i] == ']' || data[i] == '}'
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestLenientDecodeCommentsAndTrailingCommas(t *testing.T) {
+	data := []byte(`{
+		// a comment
+		"a": 1,
+		"b": [1, 2, 3,], /* trailing */
+		"c": "not // a comment",
+	}`)
+
+	var s types.Struct
+	opts := types.UnmarshalOptions{Lenient: true}
+	if err := opts.Unmarshal(data, &s); err != nil {
+		t.Fatalf("Unmarshal with Lenient: %v", err)
+	}
+	if got := s.Fields["a"].AsInterface(); got != 1.0 {
+		t.Errorf("a = %v, want 1", got)
+	}
+	if got := len(s.Fields["b"].GetListValue().GetValues()); got != 3 {
+		t.Errorf("len(b) = %d, want 3", got)
+	}
+	if got := s.Fields["c"].AsInterface(); got != "not // a comment" {
+		t.Errorf("c = %v, want %q", got, "not // a comment")
+	}
+}
+
+func TestCommentsRejectedWithoutLenient(t *testing.T) {
+	data := []byte(`{"a": 1} // trailing comment`)
+	var s types.Struct
+	if err := (types.UnmarshalOptions{}).Unmarshal(data, &s); err == nil {
+		t.Error("got nil error for trailing comment without Lenient, want error")
+	}
+}
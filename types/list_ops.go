@@ -0,0 +1,33 @@
+package types
+
+// Concat returns a new ListValue containing x's elements followed by the
+// elements of each of others, in order. x and others are not modified.
+func (x *ListValue) Concat(others ...*ListValue) *ListValue {
+	n := len(x.GetValues())
+	for _, o := range others {
+		n += len(o.GetValues())
+	}
+	out := make([]*Value, 0, n)
+	out = append(out, x.GetValues()...)
+	for _, o := range others {
+		out = append(out, o.GetValues()...)
+	}
+	return &ListValue{Values: out}
+}
+
+// Flatten returns a new ListValue with any element of x that is itself a
+// ListValue spliced into the result in place, one level deep. Non-list
+// elements are copied through unchanged. It does not recurse into the
+// spliced-in elements, so a list nested two levels deep remains a single
+// ListValue element after one Flatten call.
+func (x *ListValue) Flatten() *ListValue {
+	out := make([]*Value, 0, len(x.GetValues()))
+	for _, v := range x.GetValues() {
+		if lv := v.GetListValue(); lv != nil {
+			out = append(out, lv.GetValues()...)
+			continue
+		}
+		out = append(out, v)
+	}
+	return &ListValue{Values: out}
+}
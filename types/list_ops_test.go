@@ -0,0 +1,67 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestListValueConcatPreservesOrder(t *testing.T) {
+	a, err := types.NewList([]interface{}{1.0, 2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := types.NewList([]interface{}{3.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := types.NewList([]interface{}{4.0, 5.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := a.Concat(b, c)
+	want := []interface{}{1.0, 2.0, 3.0, 4.0, 5.0}
+	if got.Len() != len(want) {
+		t.Fatalf("Concat() has %d elements, want %d", got.Len(), len(want))
+	}
+	for i, w := range want {
+		if got.At(i).AsInterface() != w {
+			t.Errorf("element %d = %v, want %v", i, got.At(i).AsInterface(), w)
+		}
+	}
+	if a.Len() != 2 {
+		t.Errorf("Concat mutated receiver: a.Len() = %d, want 2", a.Len())
+	}
+}
+
+func TestListValueFlattenOneLevel(t *testing.T) {
+	inner, err := types.NewList([]interface{}{2.0, 3.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested := &types.ListValue{Values: []*types.Value{types.NewListValue(inner)}}
+	one, err := types.NewValue(1.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &types.ListValue{Values: []*types.Value{one, types.NewListValue(inner), types.NewListValue(nested)}}
+
+	got := l.Flatten()
+
+	// 1.0, then inner's elements (2.0, 3.0) spliced in, then nested
+	// unchanged (it only flattens one level, so the doubly-nested list
+	// inside "nested" stays a single element).
+	want := []interface{}{1.0, 2.0, 3.0}
+	if got.Len() != len(want)+1 {
+		t.Fatalf("Flatten() has %d elements, want %d", got.Len(), len(want)+1)
+	}
+	for i, w := range want {
+		if got.At(i).AsInterface() != w {
+			t.Errorf("element %d = %v, want %v", i, got.At(i).AsInterface(), w)
+		}
+	}
+	if got.At(len(want)).GetListValue() == nil {
+		t.Errorf("last element should still be a ListValue after a single Flatten")
+	}
+}
@@ -0,0 +1,19 @@
+package types
+
+import "fmt"
+
+// Slice returns a new ListValue holding x's elements in [start, end), the
+// same range convention as a Go slice expression, but returning an error
+// instead of panicking for negative, out-of-order, or out-of-range
+// indices. The returned ListValue shares no backing storage with x.
+func (x *ListValue) Slice(start, end int) (*ListValue, error) {
+	values := x.GetValues()
+	if start < 0 || end < start || end > len(values) {
+		return nil, fmt.Errorf("types: ListValue.Slice(%d, %d): out of range for length %d", start, end, len(values))
+	}
+	out := &ListValue{Values: make([]*Value, end-start)}
+	for i, v := range values[start:end] {
+		out.Values[i] = cloneValue(v)
+	}
+	return out, nil
+}
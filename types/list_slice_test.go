@@ -0,0 +1,46 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestListValueSliceValidRange(t *testing.T) {
+	lv, err := types.NewList([]interface{}{"a", "b", "c", "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := lv.Slice(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{"b", "c"}
+	for i, v := range got.Values {
+		if v.AsInterface() != want[i] {
+			t.Errorf("Slice(1, 3)[%d] = %v, want %v", i, v.AsInterface(), want[i])
+		}
+	}
+}
+
+func TestListValueSliceOutOfRange(t *testing.T) {
+	lv, err := types.NewList([]interface{}{"a", "b", "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		start, end int
+	}{
+		{-1, 2},
+		{2, 1},
+		{0, 4},
+		{4, 4},
+	}
+	for _, tt := range tests {
+		if _, err := lv.Slice(tt.start, tt.end); err == nil {
+			t.Errorf("Slice(%d, %d) = nil error, want error", tt.start, tt.end)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package types
+
+import "sort"
+
+// Sorted returns a deep copy of x with its elements sorted according to
+// CompareValues, leaving x itself untouched. The sort is stable, so
+// elements that compare equal keep their relative order; this makes
+// Sorted useful for comparing two lists as multisets regardless of their
+// original order, for example via reflect.DeepEqual or proto.Equal.
+func (x *ListValue) Sorted() *ListValue {
+	if x == nil {
+		return nil
+	}
+	out := &ListValue{Values: make([]*Value, len(x.Values))}
+	for i, v := range x.Values {
+		out.Values[i] = cloneValue(v)
+	}
+	sort.SliceStable(out.Values, func(i, j int) bool {
+		return CompareValues(out.Values[i], out.Values[j]) < 0
+	})
+	return out
+}
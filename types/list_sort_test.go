@@ -0,0 +1,42 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+func TestListValueSortedMatchesRegardlessOfOrder(t *testing.T) {
+	a, err := types.NewList([]interface{}{3.0, "b", 1.0, "a", true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := types.NewList([]interface{}{"a", true, 1.0, 3.0, "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sortedA := a.Sorted()
+	sortedB := b.Sorted()
+	if !proto.Equal(sortedA, sortedB) {
+		t.Errorf("Sorted() not equal: %v vs %v", sortedA, sortedB)
+	}
+
+	// Sorted leaves the original untouched.
+	if proto.Equal(a, sortedA) {
+		t.Error("Sorted() should return a differently-ordered copy for this input")
+	}
+}
+
+func TestListValueSortedNilAndEmpty(t *testing.T) {
+	var nilList *types.ListValue
+	if got := nilList.Sorted(); got != nil {
+		t.Errorf("Sorted() on nil = %v, want nil", got)
+	}
+
+	empty := &types.ListValue{}
+	if got := empty.Sorted(); len(got.Values) != 0 {
+		t.Errorf("Sorted() on empty = %v, want empty", got)
+	}
+}
@@ -0,0 +1,312 @@
+package types
+
+// This file implements configurable JSON encoding for Struct, ListValue,
+// and Value, complementing UnmarshalOptions/Decoder on the decode side.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// MarshalOptions configures how Struct, ListValue, and Value are encoded
+// to JSON.
+type MarshalOptions struct {
+	// EscapeHTML controls whether '<', '>', and '&' in string values are
+	// escaped as Unicode escape sequences, matching the default behavior of
+	// "encoding/json".Marshal. Use NewMarshalOptions to get a MarshalOptions
+	// with EscapeHTML set to true; the zero value has it false, for callers
+	// who want compact, non-HTML-escaped output by default.
+	EscapeHTML bool
+
+	// OmitEmptyStructs, when set, drops Struct fields whose value is a
+	// Struct with no fields, instead of emitting "{}".
+	OmitEmptyStructs bool
+	// OmitEmptyLists, when set, drops Struct fields whose value is a
+	// ListValue with no values, instead of emitting "[]".
+	OmitEmptyLists bool
+	// OmitEmptyNulls, when set, drops Struct fields whose value is a
+	// NullValue, instead of emitting "null". Equivalently, leaving it unset
+	// (the default) emits nulls for such fields, same as plain
+	// Struct.MarshalJSON; there is no separate "EmitNulls" field, since
+	// OmitEmptyNulls already names and controls the same choice, just with
+	// the opposite, encoding/json-style default of keeping data unless
+	// asked to drop it.
+	//
+	// All three Omit* options apply only to Struct fields, and recurse into
+	// nested Structs. Elements of a ListValue are never omitted, however
+	// they marshal, since removing one would shift the positions of the
+	// elements after it; this matches the stored Struct, which Marshal
+	// never mutates.
+	OmitEmptyNulls bool
+
+	// IntegerStyle controls how whole-valued NumberValues (e.g. 3, as
+	// opposed to 3.5) are rendered. The zero value, IntegerStyleCompact,
+	// matches plain Value.MarshalJSON: no decimal point. IntegerStyleDecimalPoint
+	// instead forces a trailing ".0", for schema validators that infer an
+	// integer type from the absence of a decimal point.
+	IntegerStyle IntegerStyle
+
+	// EncodeHook, when set, is called for every Value encountered while
+	// marshaling, with its dotted/bracketed path (root is ""). If it
+	// returns ok == true, its returned JSON bytes are spliced into the
+	// output verbatim in place of v's default encoding; returning ok ==
+	// false (with a nil error) falls through to the default encoding,
+	// recursing into Struct fields or ListValue elements as usual.
+	// EncodeHook is not called again on the substituted bytes.
+	EncodeHook func(path string, v *Value) (json.RawMessage, bool, error)
+
+	// FloatPrecision, when non-nil, formats every NumberValue with
+	// exactly *FloatPrecision digits after the decimal point, via
+	// strconv.AppendFloat(..., 'f', *FloatPrecision, 64), instead of the
+	// shortest round-tripping representation. It is a pointer, rather
+	// than an int defaulting to a sentinel like -1, so that the zero
+	// value of MarshalOptions (nil) unambiguously means "default
+	// shortest round-trip formatting" while still letting callers select
+	// precision 0 (whole numbers, no decimal point) explicitly.
+	FloatPrecision *int
+
+	// KeyOrder, when set, is called with a Struct's keys (already sorted
+	// lexicographically) before they are emitted, and its returned slice
+	// is used as the emission order instead. This is for APIs that expect
+	// specific fields first (e.g. "id", "type") rather than alphabetical
+	// order. KeyOrder must return a permutation of its input; it is
+	// called once per object encountered, at every nesting level.
+	KeyOrder func(keys []string) []string
+}
+
+// IntegerStyle controls how MarshalOptions renders whole-valued
+// NumberValues.
+type IntegerStyle int
+
+const (
+	// IntegerStyleCompact renders a whole-valued NumberValue without a
+	// decimal point, e.g. 3. This is the zero value.
+	IntegerStyleCompact IntegerStyle = iota
+	// IntegerStyleDecimalPoint renders a whole-valued NumberValue with a
+	// trailing ".0", e.g. 3.0. A number whose shortest form uses an
+	// exponent, e.g. 1e+21, is written in that form with no ".0" added.
+	IntegerStyleDecimalPoint
+)
+
+// NewMarshalOptions returns a MarshalOptions with EscapeHTML set to true,
+// matching "encoding/json".Marshal's default behavior.
+func NewMarshalOptions() MarshalOptions {
+	return MarshalOptions{EscapeHTML: true}
+}
+
+// Marshal encodes x, which must be a *Value, *Struct, or *ListValue (or
+// their non-pointer forms), to JSON according to o.
+func (o MarshalOptions) Marshal(x interface{}) ([]byte, error) {
+	if o.omitsEmpty() {
+		x = o.trim(x)
+	}
+	if o.IntegerStyle == IntegerStyleDecimalPoint || o.EncodeHook != nil || o.FloatPrecision != nil || o.KeyOrder != nil {
+		var buf bytes.Buffer
+		if err := o.writeStyled(&buf, x); err != nil {
+			return nil, err
+		}
+		if o.EscapeHTML {
+			var escaped bytes.Buffer
+			json.HTMLEscape(&escaped, buf.Bytes())
+			return escaped.Bytes(), nil
+		}
+		return buf.Bytes(), nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(o.EscapeHTML)
+	if err := enc.Encode(x); err != nil {
+		return nil, err
+	}
+	// Encoder.Encode always appends a trailing newline; json.Marshal does
+	// not, so trim it to keep output consistent with the rest of this
+	// package's Marshal functions.
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// writeStyled encodes x like Marshal, except that it renders whole-valued
+// NumberValues per o.IntegerStyle and consults o.EncodeHook at every
+// position, rather than delegating to Value.MarshalJSON's fixed behavior.
+func (o MarshalOptions) writeStyled(buf *bytes.Buffer, x interface{}) error {
+	var v *Value
+	switch t := x.(type) {
+	case *Value:
+		v = t
+	case Value:
+		v = &t
+	case *Struct:
+		v = NewStructValue(t)
+	case Struct:
+		v = NewStructValue(&t)
+	case *ListValue:
+		v = NewListValue(t)
+	case ListValue:
+		v = NewListValue(&t)
+	default:
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(o.EscapeHTML)
+		if err := enc.Encode(x); err != nil {
+			return err
+		}
+		b := bytes.TrimRight(buf.Bytes(), "\n")
+		buf.Reset()
+		buf.Write(b)
+		return nil
+	}
+	return o.writeValueStyled(buf, v, "")
+}
+
+func (o MarshalOptions) writeValueStyled(buf *bytes.Buffer, v *Value, path string) error {
+	if o.EncodeHook != nil {
+		raw, ok, err := o.EncodeHook(path, v)
+		if err != nil {
+			return err
+		}
+		if ok {
+			buf.Write(raw)
+			return nil
+		}
+	}
+	switch k := v.GetKind().(type) {
+	case *Value_NumberValue:
+		f := k.NumberValue
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			b, err := json.Marshal(f)
+			if err != nil {
+				return err
+			}
+			buf.Write(b)
+			return nil
+		}
+		if o.FloatPrecision != nil {
+			buf.Write(strconv.AppendFloat(nil, f, 'f', *o.FloatPrecision, 64))
+			return nil
+		}
+		s := strconv.AppendFloat(nil, f, 'g', -1, 64)
+		buf.Write(s)
+		if o.IntegerStyle == IntegerStyleDecimalPoint && !bytes.ContainsAny(s, ".eE") {
+			buf.WriteString(".0")
+		}
+		return nil
+	case *Value_StructValue:
+		sv := k.StructValue
+		keys := sv.Keys()
+		if o.KeyOrder != nil {
+			keys = o.KeyOrder(keys)
+		}
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			kb, err := marshalJSONStringRaw(key)
+			if err != nil {
+				return err
+			}
+			buf.Write(kb)
+			buf.WriteByte(':')
+			if err := o.writeValueStyled(buf, sv.Fields[key], joinPath(path, key)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case *Value_ListValue:
+		values := k.ListValue.GetValues()
+		buf.WriteByte('[')
+		for i, ev := range values {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := o.writeValueStyled(buf, ev, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	default:
+		b, err := v.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+}
+
+func (o MarshalOptions) omitsEmpty() bool {
+	return o.OmitEmptyStructs || o.OmitEmptyLists || o.OmitEmptyNulls
+}
+
+// trim returns a copy of x with empty values dropped from any Struct
+// (including nested ones) according to o, leaving x itself untouched. It
+// returns x unchanged if its type isn't one Marshal supports, so the
+// subsequent encode reports the same error it always would.
+func (o MarshalOptions) trim(x interface{}) interface{} {
+	switch v := x.(type) {
+	case *Value:
+		return o.trimValue(v)
+	case Value:
+		return *o.trimValue(&v)
+	case *Struct:
+		return o.trimStruct(v)
+	case Struct:
+		return *o.trimStruct(&v)
+	case *ListValue:
+		return o.trimList(v)
+	case ListValue:
+		return *o.trimList(&v)
+	}
+	return x
+}
+
+func (o MarshalOptions) trimValue(v *Value) *Value {
+	switch k := v.GetKind().(type) {
+	case *Value_StructValue:
+		if k != nil {
+			return &Value{Kind: &Value_StructValue{StructValue: o.trimStruct(k.StructValue)}}
+		}
+	case *Value_ListValue:
+		if k != nil {
+			return &Value{Kind: &Value_ListValue{ListValue: o.trimList(k.ListValue)}}
+		}
+	}
+	return v
+}
+
+func (o MarshalOptions) trimStruct(s *Struct) *Struct {
+	fields := s.GetFields()
+	out := &Struct{Fields: make(map[string]*Value, len(fields))}
+	for k, v := range fields {
+		tv := o.trimValue(v)
+		if o.isOmitted(tv) {
+			continue
+		}
+		out.Fields[k] = tv
+	}
+	return out
+}
+
+func (o MarshalOptions) trimList(lv *ListValue) *ListValue {
+	values := lv.GetValues()
+	out := &ListValue{Values: make([]*Value, len(values))}
+	for i, v := range values {
+		out.Values[i] = o.trimValue(v)
+	}
+	return out
+}
+
+func (o MarshalOptions) isOmitted(v *Value) bool {
+	switch k := v.GetKind().(type) {
+	case *Value_StructValue:
+		return o.OmitEmptyStructs && k.StructValue.Len() == 0
+	case *Value_ListValue:
+		return o.OmitEmptyLists && len(k.ListValue.GetValues()) == 0
+	case *Value_NullValue:
+		return o.OmitEmptyNulls
+	}
+	return false
+}
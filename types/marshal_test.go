@@ -0,0 +1,301 @@
+package types_test
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestMarshalOptionsEscapeHTML(t *testing.T) {
+	v := types.NewStringValue("<script>alert(1)</script>")
+
+	escaped, err := types.NewMarshalOptions().Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(escaped), "<script>") {
+		t.Errorf("EscapeHTML(true): got %s, want HTML-escaped", escaped)
+	}
+
+	raw, err := (types.MarshalOptions{EscapeHTML: false}).Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), "<script>") {
+		t.Errorf("EscapeHTML(false): got %s, want literal <script>", raw)
+	}
+}
+
+func TestControlCharacterRoundTrip(t *testing.T) {
+	for r := rune(0); r <= 0x1F; r++ {
+		s := string(r)
+		v := types.NewStringValue(s)
+		b, err := v.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON(%U): %v", r, err)
+		}
+		var got types.Value
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("Unmarshal(%s) (from %U): %v", b, r, err)
+		}
+		if got.GetStringValue() != s {
+			t.Errorf("round trip of %U via %s = %q, want %q", r, b, got.GetStringValue(), s)
+		}
+	}
+}
+
+func TestMarshalOptionsOmitsEmptyNestedValues(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"empty_struct": map[string]interface{}{},
+		"full_struct":  map[string]interface{}{"a": 1.0},
+		"empty_list":   []interface{}{},
+		"full_list":    []interface{}{1.0},
+		"null":         nil,
+		"kept":         "x",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := types.MarshalOptions{OmitEmptyStructs: true, OmitEmptyLists: true, OmitEmptyNulls: true}
+	b, err := o.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"full_list":[1],"full_struct":{"a":1},"kept":"x"}`
+	if string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestMarshalOptionsDefaultKeepsEmptyValues(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"empty_struct": map[string]interface{}{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var o types.MarshalOptions
+	b, err := o.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"empty_struct":{}}`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestMarshalOptionsPreservesListElementPositions(t *testing.T) {
+	lv, err := types.NewList([]interface{}{nil, map[string]interface{}{}, "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := types.MarshalOptions{OmitEmptyStructs: true, OmitEmptyNulls: true}
+	b, err := o.Marshal(lv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[null,{},"x"]`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestMarshalOptionsNullFieldEmitOrOmit(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"a": nil,
+		"b": []interface{}{nil, 1.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emitting, err := (types.MarshalOptions{}).Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"a":null,"b":[null,1]}`; string(emitting) != want {
+		t.Errorf("default Marshal() = %s, want %s", emitting, want)
+	}
+
+	omitting, err := (types.MarshalOptions{OmitEmptyNulls: true}).Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The "a" field is dropped entirely, but the null inside list "b" is
+	// kept: OmitEmptyNulls only ever applies to Struct fields, since
+	// dropping a list element would shift the ones after it.
+	if want := `{"b":[null,1]}`; string(omitting) != want {
+		t.Errorf("OmitEmptyNulls Marshal() = %s, want %s", omitting, want)
+	}
+}
+
+func TestMarshalOptionsIntegerStyleCompactIsDefault(t *testing.T) {
+	v := types.NewNumberValue(3.0)
+
+	want := `3`
+
+	var o types.MarshalOptions
+	b, err := o.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+
+	// Also locks in plain Value.MarshalJSON's existing behavior, since
+	// IntegerStyleCompact is documented to match it.
+	plain, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plain) != want {
+		t.Errorf("Value.MarshalJSON() = %s, want %s", plain, want)
+	}
+}
+
+func TestMarshalOptionsIntegerStyleDecimalPoint(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"whole": 3.0,
+		"frac":  3.5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := types.MarshalOptions{IntegerStyle: types.IntegerStyleDecimalPoint}
+	b, err := o.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"frac":3.5,"whole":3.0}`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestMarshalOptionsEncodeHookRendersSpecificField(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"amount": 12.5,
+		"other":  "x",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := types.MarshalOptions{
+		EncodeHook: func(path string, v *types.Value) (json.RawMessage, bool, error) {
+			if path != "amount" {
+				return nil, false, nil
+			}
+			return json.RawMessage(`"$12.50"`), true, nil
+		},
+	}
+	b, err := o.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"amount":"$12.50","other":"x"}`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestMarshalOptionsEncodeHookDeclineFallsThroughToDefault(t *testing.T) {
+	v := types.NewNumberValue(3.0)
+	o := types.MarshalOptions{
+		EncodeHook: func(path string, v *types.Value) (json.RawMessage, bool, error) {
+			return nil, false, nil
+		},
+	}
+	b, err := o.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `3`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestMarshalOptionsFloatPrecision(t *testing.T) {
+	v := types.NewNumberValue(3.14159)
+
+	prec := 2
+	o := types.MarshalOptions{FloatPrecision: &prec}
+	b, err := o.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `3.14`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestMarshalOptionsFloatPrecisionDefaultIsShortest(t *testing.T) {
+	v := types.NewNumberValue(3.14159)
+
+	var o types.MarshalOptions
+	b, err := o.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `3.14159`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestMarshalOptionsKeyOrderFrontLoadsSpecificKeys(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"type": "x",
+		"id":   1.0,
+		"name": "y",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	front := map[string]int{"id": 0, "type": 1}
+	o := types.MarshalOptions{
+		KeyOrder: func(keys []string) []string {
+			out := append([]string(nil), keys...)
+			sort.SliceStable(out, func(i, j int) bool {
+				pi, iok := front[out[i]]
+				pj, jok := front[out[j]]
+				if iok && jok {
+					return pi < pj
+				}
+				return iok && !jok
+			})
+			return out
+		},
+	}
+	b, err := o.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id":1,"type":"x","name":"y"}`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestSurrogatePairRoundTrip(t *testing.T) {
+	// U+1D11E MUSICAL SYMBOL G CLEF requires a surrogate pair in JSON's
+	// \uXXXX escapes, since JSON strings are UTF-16 code units.
+	s := "\U0001D11E"
+	v := types.NewStringValue(s)
+	b, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got types.Value
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal(%s): %v", b, err)
+	}
+	if got.GetStringValue() != s {
+		t.Errorf("round trip via %s = %q, want %q", b, got.GetStringValue(), s)
+	}
+}
@@ -0,0 +1,156 @@
+package types
+
+// This file implements Struct merging with configurable list-conflict
+// resolution, for layering configuration Structs on top of one another.
+
+// ListStrategy controls how MergeStructWith resolves a field that is a
+// ListValue in both the destination and the source Struct.
+type ListStrategy int
+
+const (
+	// ListReplace discards the destination's list and keeps the source's,
+	// the same behavior as merging any other non-list field.
+	ListReplace ListStrategy = iota
+	// ListAppend keeps the destination's elements first, followed by the
+	// source's elements: dst ++ src.
+	ListAppend
+	// ListConcat keeps the source's elements first, followed by the
+	// destination's elements: src ++ dst. This is useful when later
+	// layers should take priority in an ordered list (e.g. search paths).
+	ListConcat
+	// ListUnion behaves like ListAppend, but then drops later elements
+	// that are equal (per Value.Equal) to an earlier one, preserving the
+	// order of first occurrence.
+	ListUnion
+	// ListMergeByKey treats both lists as collections of Struct elements
+	// keyed by MergeOptions.MergeByKeyField: a src element whose key
+	// matches a dst element's key is deep-merged into that dst element
+	// (recursing through MergeStructWith, so nested fields layer the same
+	// way a top-level Struct field would), and a src element with a new
+	// key is appended. dst's element order is preserved; newly appended
+	// elements follow in src's order. Elements lacking the key field, or
+	// that aren't Structs at all, are never matched: such a dst element
+	// stays in place, and such a src element is appended verbatim, same as
+	// a new key.
+	ListMergeByKey
+)
+
+// MergeOptions configures MergeStructWith.
+type MergeOptions struct {
+	// ListStrategy resolves fields that are ListValues in both the
+	// destination and the source. It does not apply to fields that are a
+	// list in only one of the two, which are merged like any other field
+	// (the present value is used).
+	ListStrategy ListStrategy
+
+	// MergeByKeyField names the StringValue field identifying elements
+	// when ListStrategy is ListMergeByKey. It is ignored for every other
+	// strategy.
+	MergeByKeyField string
+}
+
+// MergeStructWith merges src into dst in place: every field in src is
+// written into dst, recursing into nested Structs present in both, and
+// resolving fields that are ListValues in both according to
+// opts.ListStrategy. For any other conflicting field, src's value wins.
+// dst must be non-nil.
+func MergeStructWith(dst, src *Struct, opts MergeOptions) {
+	if dst.Fields == nil {
+		dst.Fields = make(map[string]*Value)
+	}
+	for k, sv := range src.GetFields() {
+		dv, ok := dst.Fields[k]
+		if !ok {
+			dst.Fields[k] = sv
+			continue
+		}
+		if dStruct, ok := dv.GetKind().(*Value_StructValue); ok {
+			if sStruct, ok := sv.GetKind().(*Value_StructValue); ok {
+				MergeStructWith(dStruct.StructValue, sStruct.StructValue, opts)
+				continue
+			}
+		}
+		if dList, ok := dv.GetKind().(*Value_ListValue); ok {
+			if sList, ok := sv.GetKind().(*Value_ListValue); ok {
+				dst.Fields[k] = NewListValue(&ListValue{
+					Values: mergeLists(dList.ListValue.GetValues(), sList.ListValue.GetValues(), opts),
+				})
+				continue
+			}
+		}
+		dst.Fields[k] = sv
+	}
+}
+
+func mergeLists(dst, src []*Value, opts MergeOptions) []*Value {
+	switch opts.ListStrategy {
+	case ListMergeByKey:
+		return mergeListsByKey(dst, src, opts)
+	case ListAppend:
+		return append(append([]*Value(nil), dst...), src...)
+	case ListConcat:
+		return append(append([]*Value(nil), src...), dst...)
+	case ListUnion:
+		combined := append(append([]*Value(nil), dst...), src...)
+		out := make([]*Value, 0, len(combined))
+		for _, v := range combined {
+			dup := false
+			for _, existing := range out {
+				if existing.Equal(v) {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				out = append(out, v)
+			}
+		}
+		return out
+	default: // ListReplace
+		return append([]*Value(nil), src...)
+	}
+}
+
+// mergeListsByKey implements ListMergeByKey: dst's elements are kept in
+// place, with a matching src element (by opts.MergeByKeyField) merged into
+// it, and an unmatched src element appended.
+func mergeListsByKey(dst, src []*Value, opts MergeOptions) []*Value {
+	out := append([]*Value(nil), dst...)
+	indexByKey := make(map[string]int, len(out))
+	for i, v := range out {
+		if key, ok := structElementKey(v, opts.MergeByKeyField); ok {
+			indexByKey[key] = i
+		}
+	}
+	for _, sv := range src {
+		key, ok := structElementKey(sv, opts.MergeByKeyField)
+		if !ok {
+			out = append(out, sv)
+			continue
+		}
+		i, matched := indexByKey[key]
+		if !matched {
+			indexByKey[key] = len(out)
+			out = append(out, sv)
+			continue
+		}
+		dStruct := out[i].GetKind().(*Value_StructValue).StructValue
+		sStruct := sv.GetKind().(*Value_StructValue).StructValue
+		MergeStructWith(dStruct, sStruct, opts)
+	}
+	return out
+}
+
+// structElementKey returns v's keyField value, and whether v is a Struct
+// with that field present as a StringValue.
+func structElementKey(v *Value, keyField string) (string, bool) {
+	sv, ok := v.GetKind().(*Value_StructValue)
+	if !ok || sv == nil {
+		return "", false
+	}
+	kv, ok := sv.StructValue.GetFields()[keyField].GetKind().(*Value_StringValue)
+	if !ok || kv == nil {
+		return "", false
+	}
+	return kv.StringValue, true
+}
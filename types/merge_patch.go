@@ -0,0 +1,37 @@
+package types
+
+// This file implements RFC 7386 JSON Merge Patch, which is distinct from
+// MergeStructWith: a null in the patch deletes the target key instead of
+// setting it to null, and any non-object patch value replaces the target
+// outright rather than merging field by field.
+
+// ApplyMergePatch returns a new Struct formed by applying patch to x
+// according to RFC 7386: objects are merged recursively field by field, a
+// null in patch deletes the corresponding key from the result, and any
+// other value (including a list) replaces the target wholesale rather
+// than merging into it. x and patch are left untouched.
+func (x *Struct) ApplyMergePatch(patch *Struct) *Struct {
+	out := &Struct{Fields: make(map[string]*Value, x.Len())}
+	for k, v := range x.GetFields() {
+		out.Fields[k] = v
+	}
+	for k, pv := range patch.GetFields() {
+		if _, isNull := pv.GetKind().(*Value_NullValue); isNull {
+			delete(out.Fields, k)
+			continue
+		}
+		pStruct, pIsStruct := pv.GetKind().(*Value_StructValue)
+		if xv, ok := out.Fields[k]; ok && pIsStruct {
+			if xStruct, ok := xv.GetKind().(*Value_StructValue); ok {
+				out.Fields[k] = NewStructValue(xStruct.StructValue.ApplyMergePatch(pStruct.StructValue))
+				continue
+			}
+		}
+		if pIsStruct {
+			out.Fields[k] = NewStructValue((&Struct{}).ApplyMergePatch(pStruct.StructValue))
+			continue
+		}
+		out.Fields[k] = pv
+	}
+	return out
+}
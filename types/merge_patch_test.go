@@ -0,0 +1,80 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+func TestApplyMergePatchNullDeletes(t *testing.T) {
+	x, err := types.NewStruct(map[string]interface{}{"a": 1.0, "b": 2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, err := types.NewStruct(map[string]interface{}{"b": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := x.ApplyMergePatch(patch)
+	want, err := types.NewStruct(map[string]interface{}{"a": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("ApplyMergePatch() = %v, want %v", got, want)
+	}
+}
+
+func TestApplyMergePatchRecursiveMerge(t *testing.T) {
+	x, err := types.NewStruct(map[string]interface{}{
+		"nested": map[string]interface{}{"a": 1.0, "b": 2.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, err := types.NewStruct(map[string]interface{}{
+		"nested": map[string]interface{}{"b": nil, "c": 3.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := x.ApplyMergePatch(patch)
+	want, err := types.NewStruct(map[string]interface{}{
+		"nested": map[string]interface{}{"a": 1.0, "c": 3.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("ApplyMergePatch() = %v, want %v", got, want)
+	}
+}
+
+func TestApplyMergePatchNonObjectReplaces(t *testing.T) {
+	x, err := types.NewStruct(map[string]interface{}{
+		"list": []interface{}{1.0, 2.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, err := types.NewStruct(map[string]interface{}{
+		"list": []interface{}{3.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := x.ApplyMergePatch(patch)
+	want, err := types.NewStruct(map[string]interface{}{
+		"list": []interface{}{3.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("ApplyMergePatch() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+package types
+
+import "testing"
+
+func newTestStructWithList(t *testing.T, values []interface{}) *Struct {
+	t.Helper()
+	s, err := NewStruct(map[string]interface{}{"list": values})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func mergedList(t *testing.T, strategy ListStrategy) []interface{} {
+	t.Helper()
+	dst := newTestStructWithList(t, []interface{}{"a", "b"})
+	src := newTestStructWithList(t, []interface{}{"b", "c"})
+	MergeStructWith(dst, src, MergeOptions{ListStrategy: strategy})
+	return dst.Fields["list"].GetListValue().AsSlice()
+}
+
+func TestMergeStructWithListReplace(t *testing.T) {
+	got := mergedList(t, ListReplace)
+	want := []interface{}{"b", "c"}
+	if !slicesEqualInterface(got, want) {
+		t.Errorf("ListReplace: got %v, want %v", got, want)
+	}
+}
+
+func TestMergeStructWithListAppend(t *testing.T) {
+	got := mergedList(t, ListAppend)
+	want := []interface{}{"a", "b", "b", "c"}
+	if !slicesEqualInterface(got, want) {
+		t.Errorf("ListAppend: got %v, want %v", got, want)
+	}
+}
+
+func TestMergeStructWithListConcat(t *testing.T) {
+	got := mergedList(t, ListConcat)
+	want := []interface{}{"b", "c", "a", "b"}
+	if !slicesEqualInterface(got, want) {
+		t.Errorf("ListConcat: got %v, want %v", got, want)
+	}
+}
+
+func TestMergeStructWithListUnion(t *testing.T) {
+	got := mergedList(t, ListUnion)
+	want := []interface{}{"a", "b", "c"}
+	if !slicesEqualInterface(got, want) {
+		t.Errorf("ListUnion: got %v, want %v", got, want)
+	}
+}
+
+func TestMergeStructWithRecursesIntoNestedStructs(t *testing.T) {
+	dst, err := NewStruct(map[string]interface{}{
+		"nested": map[string]interface{}{"a": 1.0, "list": []interface{}{"x"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := NewStruct(map[string]interface{}{
+		"nested": map[string]interface{}{"b": 2.0, "list": []interface{}{"y"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	MergeStructWith(dst, src, MergeOptions{ListStrategy: ListAppend})
+
+	nested := dst.Fields["nested"].GetStructValue()
+	if got := nested.Fields["a"].AsInterface(); got != 1.0 {
+		t.Errorf("nested.a = %v, want 1.0 (preserved)", got)
+	}
+	if got := nested.Fields["b"].AsInterface(); got != 2.0 {
+		t.Errorf("nested.b = %v, want 2.0 (merged in)", got)
+	}
+	list := nested.Fields["list"].GetListValue().AsSlice()
+	if !slicesEqualInterface(list, []interface{}{"x", "y"}) {
+		t.Errorf("nested.list = %v, want [x y]", list)
+	}
+}
+
+func TestMergeStructWithListMergeByKeyMatchesAndAppends(t *testing.T) {
+	dst, err := NewStruct(map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": "1", "name": "a", "qty": 1.0},
+			map[string]interface{}{"id": "2", "name": "b"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := NewStruct(map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": "1", "qty": 5.0},
+			map[string]interface{}{"id": "3", "name": "c"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	MergeStructWith(dst, src, MergeOptions{ListStrategy: ListMergeByKey, MergeByKeyField: "id"})
+
+	items := dst.Fields["items"].GetListValue().GetValues()
+	if len(items) != 3 {
+		t.Fatalf("len(items) = %d, want 3", len(items))
+	}
+
+	first := items[0].GetStructValue()
+	if got := first.Fields["name"].AsInterface(); got != "a" {
+		t.Errorf("items[0].name = %v, want a (preserved)", got)
+	}
+	if got := first.Fields["qty"].AsInterface(); got != 5.0 {
+		t.Errorf("items[0].qty = %v, want 5.0 (merged in)", got)
+	}
+
+	third := items[2].GetStructValue()
+	if got := third.Fields["id"].AsInterface(); got != "3" {
+		t.Errorf("items[2].id = %v, want 3 (new id appended)", got)
+	}
+}
+
+func slicesEqualInterface(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package types
+
+import "strings"
+
+// MergeStructTracked merges src into dst in place, like MergeStructWith
+// with ListStrategy left at its zero value (ListReplace), while also
+// recording in provenance, keyed by dotted leaf path, the srcName of the
+// last source that set each leaf. This powers "where did this value come
+// from?" diagnostics when layering configs from multiple files. dst and
+// provenance must be non-nil.
+func MergeStructTracked(dst, src *Struct, srcName string, provenance map[string]string) {
+	mergeStructTracked(dst, src, srcName, "", provenance)
+}
+
+func mergeStructTracked(dst, src *Struct, srcName, path string, provenance map[string]string) {
+	if dst.Fields == nil {
+		dst.Fields = make(map[string]*Value)
+	}
+	for k, sv := range src.GetFields() {
+		fieldPath := joinPath(path, k)
+		dv, ok := dst.Fields[k]
+		if ok {
+			if dStruct, ok := dv.GetKind().(*Value_StructValue); ok {
+				if sStruct, ok := sv.GetKind().(*Value_StructValue); ok {
+					mergeStructTracked(dStruct.StructValue, sStruct.StructValue, srcName, fieldPath, provenance)
+					continue
+				}
+			}
+		}
+		dst.Fields[k] = sv
+		recordProvenance(sv, srcName, fieldPath, provenance)
+	}
+}
+
+func recordProvenance(v *Value, srcName, path string, provenance map[string]string) {
+	// Errors are impossible here: f below never returns a non-nil error.
+	_ = v.Walk(func(leafPath string, leaf *Value) error {
+		switch leaf.GetKind().(type) {
+		case *Value_StructValue, *Value_ListValue:
+			return nil
+		}
+		provenance[combinePath(path, leafPath)] = srcName
+		return nil
+	})
+}
+
+// combinePath joins a path prefix (already rooted at the merge's top level)
+// with a path produced by Walk starting fresh from a subtree's root ("").
+func combinePath(prefix, suffix string) string {
+	switch {
+	case prefix == "":
+		return suffix
+	case suffix == "":
+		return prefix
+	case strings.HasPrefix(suffix, "["):
+		return prefix + suffix
+	default:
+		return prefix + "." + suffix
+	}
+}
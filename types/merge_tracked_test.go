@@ -0,0 +1,44 @@
+package types
+
+import "testing"
+
+func TestMergeStructTrackedAttributesEachLeaf(t *testing.T) {
+	dst, err := NewStruct(map[string]interface{}{
+		"a": 1.0,
+		"nested": map[string]interface{}{
+			"b": "base",
+			"c": "base",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := NewStruct(map[string]interface{}{
+		"a": 2.0,
+		"nested": map[string]interface{}{
+			"b": "override",
+		},
+		"list": []interface{}{1.0, 2.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provenance := make(map[string]string)
+	MergeStructTracked(dst, src, "override.yaml", provenance)
+
+	want := map[string]string{
+		"a":        "override.yaml",
+		"nested.b": "override.yaml",
+		"list[0]":  "override.yaml",
+		"list[1]":  "override.yaml",
+	}
+	for path, srcName := range want {
+		if got := provenance[path]; got != srcName {
+			t.Errorf("provenance[%q] = %q, want %q", path, got, srcName)
+		}
+	}
+	if _, ok := provenance["nested.c"]; ok {
+		t.Errorf("provenance[nested.c] present, want untouched field to have no provenance entry")
+	}
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteNDJSON writes x's top-level fields to w as newline-delimited JSON,
+// one `{"key":...,"value":...}` object per field, in sorted key order.
+// This lets a downstream consumer stream-process a huge Struct's entries
+// without ever holding the aggregate `{...}` form in memory.
+func (x *Struct) WriteNDJSON(w io.Writer) error {
+	for _, key := range x.Keys() {
+		keyJSON, err := marshalJSONStringRaw(key)
+		if err != nil {
+			return err
+		}
+		valueJSON, err := x.Fields[key].MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "{\"key\":%s,\"value\":%s}\n", keyJSON, valueJSON); err != nil {
+			return err
+		}
+	}
+	return nil
+}
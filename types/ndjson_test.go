@@ -0,0 +1,28 @@
+package types_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructWriteNDJSON(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"b": 2.0,
+		"a": "x",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.WriteNDJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "{\"key\":\"a\",\"value\":\"x\"}\n{\"key\":\"b\",\"value\":2}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteNDJSON() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package types
+
+import (
+	"bytes"
+	"math"
+)
+
+// NonFiniteMode controls how MarshalNonFinite represents NaN and Infinity
+// NumberValues, since JSON has no native representation for them and
+// different downstream consumers expect different conventions.
+type NonFiniteMode int
+
+const (
+	// NonFiniteError fails the marshal when a non-finite NumberValue is
+	// encountered, the same behavior as calling Value.MarshalJSON directly.
+	NonFiniteError NonFiniteMode = iota
+	// NonFiniteStringForm encodes a non-finite value as one of the strings
+	// "NaN", "Infinity", or "-Infinity", matching Value.AsInterface.
+	NonFiniteStringForm
+	// NonFiniteNull encodes every non-finite value as JSON null.
+	NonFiniteNull
+)
+
+// MarshalNonFinite marshals x to JSON like Value.MarshalJSON, except that
+// NaN and Infinity NumberValues anywhere in the tree are resolved according
+// to mode instead of always erroring.
+func MarshalNonFinite(x *Value, mode NonFiniteMode) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeNonFinite(&buf, x, mode); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeNonFinite(buf *bytes.Buffer, x *Value, mode NonFiniteMode) error {
+	switch v := x.GetKind().(type) {
+	case *Value_NumberValue:
+		if v != nil && (math.IsNaN(v.NumberValue) || math.IsInf(v.NumberValue, 0)) {
+			switch mode {
+			case NonFiniteNull:
+				buf.WriteString("null")
+				return nil
+			case NonFiniteStringForm:
+				b, err := marshalJSONStringRaw(x.AsInterface().(string))
+				if err != nil {
+					return err
+				}
+				buf.Write(b)
+				return nil
+			default: // NonFiniteError
+				_, err := x.MarshalJSON()
+				return err
+			}
+		}
+	case *Value_StructValue:
+		if v != nil {
+			return writeNonFiniteStruct(buf, v.StructValue, mode)
+		}
+	case *Value_ListValue:
+		if v != nil {
+			return writeNonFiniteList(buf, v.ListValue, mode)
+		}
+	}
+	b, err := x.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+func writeNonFiniteStruct(buf *bytes.Buffer, s *Struct, mode NonFiniteMode) error {
+	buf.WriteByte('{')
+	for i, k := range s.Keys() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := marshalJSONStringRaw(k)
+		if err != nil {
+			return err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		if err := writeNonFinite(buf, s.Fields[k], mode); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func writeNonFiniteList(buf *bytes.Buffer, lv *ListValue, mode NonFiniteMode) error {
+	buf.WriteByte('[')
+	for i, v := range lv.GetValues() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := writeNonFinite(buf, v, mode); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
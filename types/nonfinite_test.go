@@ -0,0 +1,50 @@
+package types_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func structWithNaN(t *testing.T) *types.Struct {
+	t.Helper()
+	s, err := types.NewStruct(map[string]interface{}{"a": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Fields["n"] = types.NewNumberValue(math.NaN())
+	return s
+}
+
+func TestMarshalNonFiniteError(t *testing.T) {
+	s := structWithNaN(t)
+	v := types.NewStructValue(s)
+	if _, err := types.MarshalNonFinite(v, types.NonFiniteError); err == nil {
+		t.Error("got nil error for NaN in NonFiniteError mode, want error")
+	}
+}
+
+func TestMarshalNonFiniteStringForm(t *testing.T) {
+	s := structWithNaN(t)
+	v := types.NewStructValue(s)
+	b, err := types.MarshalNonFinite(v, types.NonFiniteStringForm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != `{"a":1,"n":"NaN"}` {
+		t.Errorf("MarshalNonFinite(StringForm) = %s, want {\"a\":1,\"n\":\"NaN\"}", got)
+	}
+}
+
+func TestMarshalNonFiniteNull(t *testing.T) {
+	s := structWithNaN(t)
+	v := types.NewStructValue(s)
+	b, err := types.MarshalNonFinite(v, types.NonFiniteNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != `{"a":1,"n":null}` {
+		t.Errorf("MarshalNonFinite(Null) = %s, want {\"a\":1,\"n\":null}", got)
+	}
+}
@@ -0,0 +1,25 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// TestValueUnmarshalRejectsStrconvOnlyNumericTokens guards against
+// strconv.ParseFloat accepting "inf"/"nan" (case-insensitively) and hex
+// float literals like "0x10", none of which are valid JSON numbers.
+// isJSONNumber's grammar check rejects all of them; "nan" additionally
+// used to be silently misparsed as a NullValue since the 'n'-prefix
+// literal-null branch didn't check for the full "null" token.
+func TestValueUnmarshalRejectsStrconvOnlyNumericTokens(t *testing.T) {
+	for _, s := range []string{"inf", "Inf", "-inf", "nan", "NaN", "0x10", "0x1p-2"} {
+		var v types.Value
+		if err := v.UnmarshalJSON([]byte(s)); err == nil {
+			t.Errorf("UnmarshalJSON(%q) = nil error, want error", s)
+		}
+		if v.GetKind() != nil {
+			t.Errorf("UnmarshalJSON(%q) set Kind = %v, want untouched on error", s, v.GetKind())
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// TestValueUnmarshalRejectsNonJSONNumberTokens guards against a regression
+// found by the fuzz round-trip test: strconv.ParseFloat accepts tokens
+// like "NaN" and "Infinity" that are not valid JSON numbers, which used to
+// let Value.UnmarshalJSON decode them into a NumberValue that
+// Value.MarshalJSON could then not re-encode (encoding/json rejects
+// non-finite floats), breaking the round trip.
+func TestValueUnmarshalRejectsNonJSONNumberTokens(t *testing.T) {
+	for _, s := range []string{"NaN", "NAN", "Infinity", "-Infinity", "+1", "01", "1.", ".1", "1e"} {
+		var v types.Value
+		if err := v.UnmarshalJSON([]byte(s)); err == nil {
+			t.Errorf("UnmarshalJSON(%q) = nil error, want error", s)
+		}
+	}
+}
+
+func TestValueUnmarshalAcceptsValidJSONNumbers(t *testing.T) {
+	for _, s := range []string{"0", "-0", "3", "-3", "3.5", "1e10", "1E10", "1e+10", "1e-10", "0.5"} {
+		var v types.Value
+		if err := v.UnmarshalJSON([]byte(s)); err != nil {
+			t.Errorf("UnmarshalJSON(%q) error: %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// TestValueUnmarshalRejectsUnderscoreSeparatedNumbers guards against
+// strconv.ParseFloat's acceptance of Go-style digit-separator underscores
+// (e.g. "1_000"), which are not valid JSON number tokens; isJSONNumber's
+// grammar check, not strconv.ParseFloat's looser one, decides whether a
+// bare token is a number.
+func TestValueUnmarshalRejectsUnderscoreSeparatedNumbers(t *testing.T) {
+	for _, s := range []string{"1_000", "1_000.5", "1e1_0"} {
+		var v types.Value
+		if err := v.UnmarshalJSON([]byte(s)); err == nil {
+			t.Errorf("UnmarshalJSON(%q) = nil error, want error", s)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package types
+
+// This file implements lossless round-tripping of JSON numbers through a
+// Struct/Value tree, pairing with UnmarshalOptions.PreserveNumbersAsStrings
+// on the decode side. float64 cannot represent every decimal exactly (e.g.
+// 0.1) or every integer beyond 2^53, which a financial pipeline may not be
+// able to tolerate; storing the original token text sidesteps that.
+
+import (
+	"bytes"
+	"strings"
+)
+
+// numericTagPrefix marks a StringValue as holding a verbatim JSON number
+// token rather than a genuine string, for UnmarshalOptions.
+// PreserveNumbersAsStrings and MarshalPreservingNumbers. It starts with a
+// NUL byte, which cannot appear in a valid JSON-decoded Go string produced
+// by any normal input, making collisions with real string content
+// effectively impossible.
+const numericTagPrefix = "\x00num\x00"
+
+// stripNumericTag reports whether s was tagged by
+// PreserveNumbersAsStrings, returning the original verbatim number text if
+// so.
+func stripNumericTag(s string) (string, bool) {
+	if strings.HasPrefix(s, numericTagPrefix) {
+		return s[len(numericTagPrefix):], true
+	}
+	return "", false
+}
+
+// untagNumericString strips a PreserveNumbersAsStrings tag from s if
+// present, returning s unchanged otherwise. General-purpose accessors
+// (AsInterface, the ordinary MarshalJSON) that don't know about the
+// preservation feature call this so they see the original number text as
+// a plain string instead of the internal tag.
+func untagNumericString(s string) string {
+	if orig, ok := stripNumericTag(s); ok {
+		return orig
+	}
+	return s
+}
+
+// MarshalPreservingNumbers encodes x to JSON like x.MarshalJSON, except
+// that StringValues tagged by UnmarshalOptions.PreserveNumbersAsStrings
+// are emitted as the verbatim, unquoted number token they were decoded
+// from, instead of a quoted string. Passing a Value not produced with that
+// option is equivalent to calling its ordinary MarshalJSON.
+func MarshalPreservingNumbers(x *Value) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writePreservingNumbers(&buf, x); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writePreservingNumbers(buf *bytes.Buffer, x *Value) error {
+	switch k := x.GetKind().(type) {
+	case *Value_StringValue:
+		if num, ok := stripNumericTag(k.StringValue); ok {
+			buf.WriteString(num)
+			return nil
+		}
+		b, err := marshalJSONStringRaw(k.StringValue)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	case *Value_StructValue:
+		sv := k.StructValue
+		keys := sv.Keys()
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			kb, err := marshalJSONStringRaw(key)
+			if err != nil {
+				return err
+			}
+			buf.Write(kb)
+			buf.WriteByte(':')
+			if err := writePreservingNumbers(buf, sv.Fields[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case *Value_ListValue:
+		values := k.ListValue.GetValues()
+		buf.WriteByte('[')
+		for i, v := range values {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writePreservingNumbers(buf, v); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	default:
+		b, err := x.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+}
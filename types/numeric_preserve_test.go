@@ -0,0 +1,71 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestPreserveNumbersAsStringsRoundTrip(t *testing.T) {
+	tests := []string{
+		"0.30000000000000004",
+		"1234567890123456789012345678901234567890",
+	}
+	for _, num := range tests {
+		data := []byte(`{"a":` + num + `}`)
+		var s types.Struct
+		opts := types.UnmarshalOptions{PreserveNumbersAsStrings: true}
+		if err := opts.Unmarshal(data, &s); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+
+		got, err := types.MarshalPreservingNumbers(types.NewStructValue(&s))
+		if err != nil {
+			t.Fatalf("MarshalPreservingNumbers: %v", err)
+		}
+		if string(got) != string(data) {
+			t.Errorf("round trip of %s = %s, want %s", data, got, data)
+		}
+	}
+}
+
+func TestPreserveNumbersAsStringsWithoutMatchingMarshalIsTagged(t *testing.T) {
+	var s types.Struct
+	opts := types.UnmarshalOptions{PreserveNumbersAsStrings: true}
+	if err := opts.Unmarshal([]byte(`{"a":42}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Fields["a"].GetKind().(*types.Value_StringValue); !ok {
+		t.Errorf("a = %v, want tagged StringValue", s.Fields["a"])
+	}
+}
+
+// TestPreserveNumbersAsStringsTagDoesNotLeakThroughGeneralAccessors guards
+// against the internal PreserveNumbersAsStrings tag (and its leading NUL
+// byte) reaching a caller that doesn't know to use MarshalPreservingNumbers:
+// AsInterface/AsMap and the ordinary MarshalJSON must see the plain decoded
+// number text instead.
+func TestPreserveNumbersAsStringsTagDoesNotLeakThroughGeneralAccessors(t *testing.T) {
+	var s types.Struct
+	opts := types.UnmarshalOptions{PreserveNumbersAsStrings: true}
+	if err := opts.Unmarshal([]byte(`{"a":42}`), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := s.Fields["a"].AsInterface(), interface{}("42"); got != want {
+		t.Errorf("AsInterface() = %q, want %q", got, want)
+	}
+
+	m := s.AsMap()
+	if got, want := m["a"], interface{}("42"); got != want {
+		t.Errorf("AsMap()[\"a\"] = %q, want %q", got, want)
+	}
+
+	b, err := s.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	if got, want := string(b), `{"a":"42"}`; got != want {
+		t.Errorf("MarshalJSON() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	strconv "strconv"
+)
+
+// AsInt64InRange returns x's NumberValue as an int64, erroring if x is not a
+// NumberValue, if the number is not integral, if it falls outside the range
+// a float64 can represent exactly (±2^53), or if it falls outside [min,max].
+// This centralizes the integrality-and-bounds checks needed before trusting
+// a numeric field from an untrusted Struct.
+func (x *Value) AsInt64InRange(min, max int64) (int64, error) {
+	nv, ok := x.GetKind().(*Value_NumberValue)
+	if !ok {
+		return 0, fmt.Errorf("AsInt64InRange: Value is not a NumberValue")
+	}
+	f := nv.NumberValue
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("AsInt64InRange: %v is not an integer", f)
+	}
+	const maxExactFloat = 1 << 53
+	if f < -maxExactFloat || f > maxExactFloat {
+		return 0, fmt.Errorf("AsInt64InRange: %v is outside the range a float64 can represent exactly", f)
+	}
+	n := int64(f)
+	if n < min || n > max {
+		return 0, fmt.Errorf("AsInt64InRange: %d is outside [%d, %d]", n, min, max)
+	}
+	return n, nil
+}
+
+// AsUint64 returns x's value as a uint64. If x is a StringValue, such as
+// one produced by NewUint64Value or NewInt64Value, it is parsed as a
+// decimal integer and returned exactly, regardless of magnitude. Otherwise
+// x must be a NumberValue, decoded with the same integrality-and-exact-
+// range checks as AsInt64InRange, additionally erroring if the number is
+// negative.
+func (x *Value) AsUint64() (uint64, error) {
+	if sv, ok := x.GetKind().(*Value_StringValue); ok {
+		n, err := strconv.ParseUint(sv.StringValue, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("AsUint64: %v", err)
+		}
+		return n, nil
+	}
+	nv, ok := x.GetKind().(*Value_NumberValue)
+	if !ok {
+		return 0, fmt.Errorf("AsUint64: Value is not a NumberValue")
+	}
+	f := nv.NumberValue
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("AsUint64: %v is not an integer", f)
+	}
+	if f < 0 {
+		return 0, fmt.Errorf("AsUint64: %v is negative", f)
+	}
+	const maxExactFloat = 1 << 53
+	if f > maxExactFloat {
+		return 0, fmt.Errorf("AsUint64: %v is outside the range a float64 can represent exactly", f)
+	}
+	return uint64(f), nil
+}
+
+// AsInt64 returns x's value as an int64. If x is a StringValue, such as
+// one produced by NewInt64Value or NewUint64Value, it is parsed as a
+// decimal integer and returned exactly, regardless of magnitude. Otherwise
+// x must be a NumberValue, decoded with the same integrality-and-exact-
+// range checks as AsInt64InRange, bounded only by what an int64 itself can
+// hold.
+func (x *Value) AsInt64() (int64, error) {
+	if sv, ok := x.GetKind().(*Value_StringValue); ok {
+		n, err := strconv.ParseInt(sv.StringValue, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("AsInt64: %v", err)
+		}
+		return n, nil
+	}
+	return x.AsInt64InRange(math.MinInt64, math.MaxInt64)
+}
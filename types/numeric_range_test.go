@@ -0,0 +1,42 @@
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestAsInt64InRange(t *testing.T) {
+	v := types.NewNumberValue(42)
+	n, err := v.AsInt64InRange(0, 100)
+	if err != nil || n != 42 {
+		t.Fatalf("AsInt64InRange(0, 100) = %d, %v, want 42, nil", n, err)
+	}
+
+	if _, err := v.AsInt64InRange(0, 10); err == nil {
+		t.Error("got nil error for out-of-range value, want error")
+	} else if !strings.Contains(err.Error(), "outside") {
+		t.Errorf("error %q does not mention being out of range", err)
+	}
+
+	nonIntegral := types.NewNumberValue(1.5)
+	if _, err := nonIntegral.AsInt64InRange(0, 10); err == nil {
+		t.Error("got nil error for non-integral value, want error")
+	} else if !strings.Contains(err.Error(), "not an integer") {
+		t.Errorf("error %q does not mention non-integral value", err)
+	}
+}
+
+func TestAsUint64(t *testing.T) {
+	u, err := types.NewNumberValue(7).AsUint64()
+	if err != nil || u != 7 {
+		t.Fatalf("AsUint64() = %d, %v, want 7, nil", u, err)
+	}
+
+	if _, err := types.NewNumberValue(-1).AsUint64(); err == nil {
+		t.Error("got nil error for negative value, want error")
+	} else if !strings.Contains(err.Error(), "negative") {
+		t.Errorf("error %q does not mention negative value", err)
+	}
+}
@@ -0,0 +1,26 @@
+package types
+
+import "strconv"
+
+// ParseNumberValue parses s as a JSON number literal and returns the
+// resulting NumberValue, applying the exact same grammar the decoder uses
+// for a bare (unquoted) number token (see isJSONNumber), rather than
+// strconv.ParseFloat's looser acceptance. Use this wherever a string needs
+// to be interpreted as a number exactly as the decoder would, such as a
+// custom DecodeHook; note this is stricter than UnmarshalOptions'
+// CoerceStringNumbers, which coerces quoted strings via plain ParseFloat
+// and intentionally accepts things like "00501" that aren't valid bare
+// JSON numbers. It returns ok == false, rather than an error, for anything
+// that isn't a valid JSON number (such as "NaN", "0x10", or "1_000"),
+// since that is the expected, common outcome for arbitrary string content,
+// not an exceptional one.
+func ParseNumberValue(s string) (v *Value, ok bool) {
+	if !isJSONNumber([]byte(s)) {
+		return nil, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, false
+	}
+	return NewNumberValue(f), true
+}
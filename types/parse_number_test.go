@@ -0,0 +1,33 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestParseNumberValue(t *testing.T) {
+	tests := []struct {
+		s    string
+		want float64
+		ok   bool
+	}{
+		{"42", 42, true},
+		{"-3.5", -3.5, true},
+		{"1e10", 1e10, true},
+		{"NaN", 0, false},
+		{"0x10", 0, false},
+		{"00501", 0, false},
+		{"not a number", 0, false},
+	}
+	for _, tt := range tests {
+		v, ok := types.ParseNumberValue(tt.s)
+		if ok != tt.ok {
+			t.Errorf("ParseNumberValue(%q) ok = %v, want %v", tt.s, ok, tt.ok)
+			continue
+		}
+		if ok && v.GetNumberValue() != tt.want {
+			t.Errorf("ParseNumberValue(%q) = %v, want %v", tt.s, v.GetNumberValue(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// PopulateMessage fills m's fields from x, matching Struct keys against m's
+// protobuf JSON field names (accepting either the original or camelCase
+// name), effectively a Struct-driven jsonpb unmarshal that skips the
+// intermediate JSON bytes. This is useful for building a message directly
+// from config-shaped data that is already held as a Struct.
+//
+// It supports scalar, bytes, nested message, and repeated fields, with
+// enums matched by numeric value rather than name. It does not support
+// oneofs, maps, or well-known-type fields (Timestamp, Duration, Any, and
+// the wrapper types); populate those individually after calling
+// PopulateMessage. Type mismatches are reported with the offending field's
+// path for context.
+func PopulateMessage(m proto.Message, x *Struct) error {
+	rv := reflect.ValueOf(m)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("PopulateMessage: m must be a non-nil pointer to a message")
+	}
+	if x == nil {
+		return nil
+	}
+	return populateMessage(rv.Elem(), x)
+}
+
+func populateMessage(target reflect.Value, x *Struct) error {
+	sprops := proto.GetProperties(target.Type())
+	for i := 0; i < target.NumField(); i++ {
+		if strings.HasPrefix(target.Type().Field(i).Name, "XXX_") {
+			continue
+		}
+		prop := sprops.Prop[i]
+		if prop == nil || prop.OrigName == "" {
+			continue
+		}
+		fv, ok := x.GetFields()[prop.JSONName]
+		if !ok {
+			fv, ok = x.GetFields()[prop.OrigName]
+		}
+		if !ok {
+			continue
+		}
+		if err := populateField(target.Field(i), fv, prop); err != nil {
+			return fmt.Errorf("field %q: %v", prop.OrigName, err)
+		}
+	}
+	return nil
+}
+
+func populateField(field reflect.Value, v *Value, prop *proto.Properties) error {
+	if field.Kind() == reflect.Map {
+		return fmt.Errorf("unsupported map field")
+	}
+	if prop.Repeated {
+		return populateRepeatedField(field, v)
+	}
+	if field.Kind() == reflect.Ptr {
+		if _, isNull := v.GetKind().(*Value_NullValue); isNull {
+			return nil
+		}
+		sv, ok := v.GetKind().(*Value_StructValue)
+		if !ok {
+			return fmt.Errorf("expected object, got %T", v.GetKind())
+		}
+		nv := reflect.New(field.Type().Elem())
+		if err := populateMessage(nv.Elem(), sv.StructValue); err != nil {
+			return err
+		}
+		field.Set(nv)
+		return nil
+	}
+	return populateScalar(field, v)
+}
+
+func populateRepeatedField(field reflect.Value, v *Value) error {
+	lv, ok := v.GetKind().(*Value_ListValue)
+	if !ok {
+		return fmt.Errorf("expected array, got %T", v.GetKind())
+	}
+	values := lv.ListValue.GetValues()
+	elemType := field.Type().Elem()
+	slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+	for i, ev := range values {
+		elem := slice.Index(i)
+		if elemType.Kind() == reflect.Ptr {
+			sv, ok := ev.GetKind().(*Value_StructValue)
+			if !ok {
+				return fmt.Errorf("index %d: expected object, got %T", i, ev.GetKind())
+			}
+			nv := reflect.New(elemType.Elem())
+			if err := populateMessage(nv.Elem(), sv.StructValue); err != nil {
+				return fmt.Errorf("index %d: %v", i, err)
+			}
+			elem.Set(nv)
+			continue
+		}
+		if err := populateScalar(elem, ev); err != nil {
+			return fmt.Errorf("index %d: %v", i, err)
+		}
+	}
+	field.Set(slice)
+	return nil
+}
+
+func populateScalar(field reflect.Value, v *Value) error {
+	switch field.Kind() {
+	case reflect.Bool:
+		b, ok := v.GetKind().(*Value_BoolValue)
+		if !ok {
+			return fmt.Errorf("expected bool, got %T", v.GetKind())
+		}
+		field.SetBool(b.BoolValue)
+	case reflect.String:
+		s, ok := v.GetKind().(*Value_StringValue)
+		if !ok {
+			return fmt.Errorf("expected string, got %T", v.GetKind())
+		}
+		field.SetString(s.StringValue)
+	case reflect.Int32, reflect.Int64:
+		n, ok := v.GetKind().(*Value_NumberValue)
+		if !ok {
+			return fmt.Errorf("expected number, got %T", v.GetKind())
+		}
+		field.SetInt(int64(n.NumberValue))
+	case reflect.Uint32, reflect.Uint64:
+		n, ok := v.GetKind().(*Value_NumberValue)
+		if !ok {
+			return fmt.Errorf("expected number, got %T", v.GetKind())
+		}
+		field.SetUint(uint64(n.NumberValue))
+	case reflect.Float32, reflect.Float64:
+		n, ok := v.GetKind().(*Value_NumberValue)
+		if !ok {
+			return fmt.Errorf("expected number, got %T", v.GetKind())
+		}
+		field.SetFloat(n.NumberValue)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported field type %s", field.Type())
+		}
+		s, ok := v.GetKind().(*Value_StringValue)
+		if !ok {
+			return fmt.Errorf("expected base64 string, got %T", v.GetKind())
+		}
+		b, err := base64.StdEncoding.DecodeString(s.StringValue)
+		if err != nil {
+			return fmt.Errorf("bad base64: %v", err)
+		}
+		field.SetBytes(b)
+	default:
+		return fmt.Errorf("unsupported field kind %s", field.Kind())
+	}
+	return nil
+}
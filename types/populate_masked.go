@@ -0,0 +1,21 @@
+package types
+
+import "github.com/gogo/protobuf/proto"
+
+// PopulateMessageMasked is like PopulateMessage, but only applies the
+// fields of x named by mask's paths, leaving every other field of m
+// untouched. This supports partial-update handlers that accept a dynamic
+// Struct plus a mask describing which of its fields the caller actually
+// intends to change.
+//
+// A dotted path (e.g. "a.b") selects a nested subtree the same way
+// ApplyFieldMask does; note that, as with PopulateMessage's handling of
+// nested messages generally, populating such a path replaces m's entire
+// nested message at "a" with a new one built from just the masked
+// subtree, rather than merging "b" into whatever "a" already held. For
+// masks that only name top-level fields, which is the common case for
+// partial-update handlers, the other fields of m are left exactly as they
+// were.
+func PopulateMessageMasked(m proto.Message, x *Struct, mask *FieldMask) error {
+	return PopulateMessage(m, x.ApplyFieldMask(mask))
+}
@@ -0,0 +1,34 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestPopulateMessageMaskedOnlyAppliesMaskedFields(t *testing.T) {
+	x, err := types.NewStruct(map[string]interface{}{
+		"name":   "Ada",
+		"age":    36.0,
+		"active": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := testPerson{Name: "placeholder", Age: 1, Active: false}
+	mask := &types.FieldMask{Paths: []string{"name", "active"}}
+	if err := types.PopulateMessageMasked(&p, x, mask); err != nil {
+		t.Fatalf("PopulateMessageMasked: %v", err)
+	}
+
+	if p.Name != "Ada" {
+		t.Errorf("Name = %q, want Ada (masked in)", p.Name)
+	}
+	if !p.Active {
+		t.Error("Active = false, want true (masked in)")
+	}
+	if p.Age != 1 {
+		t.Errorf("Age = %d, want 1 (unmasked, left untouched)", p.Age)
+	}
+}
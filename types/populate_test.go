@@ -0,0 +1,91 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// testAddress and testPerson are hand-written stand-ins for generated
+// messages, tagged the way protoc-gen-gogo would tag them, so that
+// PopulateMessage can be exercised without depending on a generated test
+// package.
+type testAddress struct {
+	City string `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+}
+
+func (m *testAddress) Reset()         { *m = testAddress{} }
+func (m *testAddress) String() string { return "" }
+func (m *testAddress) ProtoMessage()  {}
+
+type testPerson struct {
+	Name    string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Age     int32        `protobuf:"varint,2,opt,name=age,proto3" json:"age,omitempty"`
+	Active  bool         `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	Address *testAddress `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	Tags    []string     `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (m *testPerson) Reset()         { *m = testPerson{} }
+func (m *testPerson) String() string { return "" }
+func (m *testPerson) ProtoMessage()  {}
+
+func TestPopulateMessageScalarAndNested(t *testing.T) {
+	x, err := types.NewStruct(map[string]interface{}{
+		"name":   "Ada",
+		"age":    36.0,
+		"active": true,
+		"address": map[string]interface{}{
+			"city": "London",
+		},
+		"tags": []interface{}{"math", "computing"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p testPerson
+	if err := types.PopulateMessage(&p, x); err != nil {
+		t.Fatalf("PopulateMessage: %v", err)
+	}
+
+	if p.Name != "Ada" {
+		t.Errorf("Name = %q, want Ada", p.Name)
+	}
+	if p.Age != 36 {
+		t.Errorf("Age = %d, want 36", p.Age)
+	}
+	if !p.Active {
+		t.Error("Active = false, want true")
+	}
+	if p.Address == nil || p.Address.City != "London" {
+		t.Errorf("Address = %+v, want City=London", p.Address)
+	}
+	if want := []string{"math", "computing"}; len(p.Tags) != len(want) || p.Tags[0] != want[0] || p.Tags[1] != want[1] {
+		t.Errorf("Tags = %v, want %v", p.Tags, want)
+	}
+}
+
+func TestPopulateMessageTypeMismatch(t *testing.T) {
+	x, err := types.NewStruct(map[string]interface{}{"age": "not a number"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p testPerson
+	if err := types.PopulateMessage(&p, x); err == nil {
+		t.Fatal("got nil error, want error")
+	}
+}
+
+func TestPopulateMessageMapFieldReturnsError(t *testing.T) {
+	x, err := types.NewStruct(map[string]interface{}{
+		"tags": []interface{}{"a", "b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p testLabels
+	if err := types.PopulateMessage(&p, x); err == nil {
+		t.Fatal("got nil error, want error for unsupported map field")
+	}
+}
@@ -0,0 +1,49 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestPrecisionLossHookFiresForLargeIntegers(t *testing.T) {
+	data := []byte(`{"id":9007199254740993,"small":42}`)
+
+	var calls []struct {
+		path, raw string
+	}
+	opts := types.UnmarshalOptions{
+		PrecisionLossHook: func(path, raw string) {
+			calls = append(calls, struct{ path, raw string }{path, raw})
+		},
+	}
+	var s types.Struct
+	if err := opts.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 1 {
+		t.Fatalf("len(calls) = %d, want 1 (calls: %v)", len(calls), calls)
+	}
+	if calls[0].path != "id" || calls[0].raw != "9007199254740993" {
+		t.Errorf("calls[0] = %+v, want {id 9007199254740993}", calls[0])
+	}
+}
+
+func TestPrecisionLossHookSilentForExactIntegers(t *testing.T) {
+	data := []byte(`{"small":42}`)
+
+	fired := false
+	opts := types.UnmarshalOptions{
+		PrecisionLossHook: func(path, raw string) {
+			fired = true
+		},
+	}
+	var s types.Struct
+	if err := opts.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	if fired {
+		t.Error("PrecisionLossHook fired for an exactly representable integer")
+	}
+}
@@ -0,0 +1,20 @@
+package types
+
+import "strings"
+
+// Project returns a new Struct containing only the fields named by paths,
+// a simpler path-list alternative to ApplyFieldMask for callers who don't
+// otherwise need a FieldMask. Dotted paths (e.g. "a.b") select a nested
+// subtree, creating intermediate Structs in the result as needed. A path
+// whose parent is not a Struct, or that does not exist in x, is silently
+// skipped.
+func (x *Struct) Project(paths []string) *Struct {
+	out := &Struct{Fields: make(map[string]*Value)}
+	for _, path := range paths {
+		segs := strings.Split(path, ".")
+		if v, ok := lookupPath(x, segs); ok {
+			setPath(out, segs, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+package types
+
+import "testing"
+
+func TestStructProject(t *testing.T) {
+	s, err := NewStruct(map[string]interface{}{
+		"name":   "alice",
+		"secret": "hunter2",
+		"nested": map[string]interface{}{"a": 1.0, "b": 2.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := s.Project([]string{"name", "nested.a", "missing", "missing.too"})
+
+	if got.Len() != 2 {
+		t.Fatalf("Project result has %d top-level fields, want 2: %v", got.Len(), got.Keys())
+	}
+	if v := got.Fields["name"].GetStringValue(); v != "alice" {
+		t.Errorf("name = %q, want alice", v)
+	}
+	nested := got.Fields["nested"].GetStructValue()
+	if nested == nil || nested.Fields["a"].AsInterface() != 1.0 {
+		t.Errorf("nested.a missing or wrong: %v", got.Fields["nested"])
+	}
+	if _, ok := nested.Fields["b"]; ok {
+		t.Errorf("nested.b should not be projected, got %v", nested.Fields["b"])
+	}
+	if _, ok := got.Fields["secret"]; ok {
+		t.Errorf("secret should not be projected")
+	}
+}
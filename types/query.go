@@ -0,0 +1,110 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// querySegment is one step of a parsed Query expression: either a field
+// name, a wildcard list index ("[*]"), or a specific list index
+// ("[<n>]").
+type querySegment struct {
+	name     string
+	wildcard bool
+	index    int
+	isIndex  bool
+}
+
+// Query evaluates a small subset of JSONPath against x and returns every
+// matching Value. Supported syntax:
+//
+//   - a leading "$" denoting the root, optionally followed by "." or "["
+//   - ".name" to select a Struct field
+//   - "[*]" to select every element of a ListValue
+//   - "[<n>]" to select a specific ListValue element by index
+//
+// For example, "$.items[*].id" selects the "id" field of every element
+// of the "items" list. A segment that doesn't apply to the current
+// Value (a field lookup on a non-Struct, an index on a non-ListValue, a
+// missing field, or an out-of-range index) simply contributes no
+// matches, rather than an error; Query only returns an error for a
+// malformed expr.
+func (x *Struct) Query(expr string) ([]*Value, error) {
+	segs, err := parseQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	return evalQuery([]*Value{NewStructValue(x)}, segs), nil
+}
+
+func parseQuery(expr string) ([]querySegment, error) {
+	s := strings.TrimSpace(expr)
+	s = strings.TrimPrefix(s, "$")
+
+	var segs []querySegment
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			end := strings.IndexAny(s, ".[")
+			if end == -1 {
+				end = len(s)
+			}
+			name := s[:end]
+			if name == "" {
+				return nil, fmt.Errorf("types: Query(%q): empty field name", expr)
+			}
+			segs = append(segs, querySegment{name: name})
+			s = s[end:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("types: Query(%q): unterminated '['", expr)
+			}
+			inner := s[1:end]
+			if inner == "*" {
+				segs = append(segs, querySegment{wildcard: true})
+			} else {
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("types: Query(%q): invalid index %q", expr, inner)
+				}
+				segs = append(segs, querySegment{isIndex: true, index: n})
+			}
+			s = s[end+1:]
+		default:
+			return nil, fmt.Errorf("types: Query(%q): unexpected character %q", expr, s[0])
+		}
+	}
+	return segs, nil
+}
+
+func evalQuery(current []*Value, segs []querySegment) []*Value {
+	for _, seg := range segs {
+		var next []*Value
+		for _, v := range current {
+			switch {
+			case seg.wildcard:
+				if lv, ok := v.GetKind().(*Value_ListValue); ok {
+					next = append(next, lv.ListValue.GetValues()...)
+				}
+			case seg.isIndex:
+				if lv, ok := v.GetKind().(*Value_ListValue); ok {
+					values := lv.ListValue.GetValues()
+					if seg.index >= 0 && seg.index < len(values) {
+						next = append(next, values[seg.index])
+					}
+				}
+			default:
+				if sv, ok := v.GetKind().(*Value_StructValue); ok {
+					if fv, ok := sv.StructValue.GetFields()[seg.name]; ok {
+						next = append(next, fv)
+					}
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
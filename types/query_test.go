@@ -0,0 +1,97 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructQueryWildcardOverList(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": "a"},
+			map[string]interface{}{"id": "b"},
+			map[string]interface{}{"id": "c"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Query("$.items[*].id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, v := range got {
+		ids = append(ids, v.GetStringValue())
+	}
+	want := []string{"a", "b", "c"}
+	if len(ids) != len(want) {
+		t.Fatalf("Query() = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("Query()[%d] = %q, want %q", i, ids[i], want[i])
+		}
+	}
+}
+
+func TestStructQueryPlainNestedPath(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"c": 42.0},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Query("$.a.b.c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].GetNumberValue() != 42 {
+		t.Errorf("Query() = %v, want [42]", got)
+	}
+}
+
+func TestStructQueryNumericIndex(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"items": []interface{}{"x", "y", "z"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Query("$.items[1]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].GetStringValue() != "y" {
+		t.Errorf("Query() = %v, want [y]", got)
+	}
+}
+
+func TestStructQueryMissingPathYieldsNoMatches(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{"a": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Query("$.missing.path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Query() = %v, want none", got)
+	}
+}
+
+func TestStructQueryMalformedExpr(t *testing.T) {
+	s, _ := types.NewStruct(nil)
+	if _, err := s.Query("$.items[1"); err == nil {
+		t.Error("Query() with unterminated '[' = nil error, want error")
+	}
+}
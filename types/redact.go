@@ -0,0 +1,53 @@
+package types
+
+import "strings"
+
+// Redact returns a deep copy of x with any field whose key is in keys
+// replaced by replacement, recursing into nested structs and lists. If
+// replacement is nil, it defaults to a StringValue "***". Matching is
+// case-sensitive; to match case-insensitively, populate keys with
+// lowercased keys and pass them through strings.ToLower when checking,
+// e.g. via RedactFold.
+func (x *Struct) Redact(keys map[string]struct{}, replacement *Value) *Struct {
+	return x.redact(keys, replacement, false)
+}
+
+// RedactFold is like Redact, but matches keys case-insensitively. keys
+// should contain lowercased key names.
+func (x *Struct) RedactFold(keys map[string]struct{}, replacement *Value) *Struct {
+	return x.redact(keys, replacement, true)
+}
+
+func (x *Struct) redact(keys map[string]struct{}, replacement *Value, fold bool) *Struct {
+	if replacement == nil {
+		replacement = NewStringValue("***")
+	}
+	out := &Struct{Fields: make(map[string]*Value, len(x.GetFields()))}
+	for k, v := range x.GetFields() {
+		matchKey := k
+		if fold {
+			matchKey = strings.ToLower(k)
+		}
+		if _, ok := keys[matchKey]; ok {
+			out.Fields[k] = replacement
+			continue
+		}
+		out.Fields[k] = redactValue(v, keys, replacement, fold)
+	}
+	return out
+}
+
+func redactValue(v *Value, keys map[string]struct{}, replacement *Value, fold bool) *Value {
+	switch k := v.GetKind().(type) {
+	case *Value_StructValue:
+		return NewStructValue(k.StructValue.redact(keys, replacement, fold))
+	case *Value_ListValue:
+		values := make([]*Value, len(k.ListValue.GetValues()))
+		for i, child := range k.ListValue.GetValues() {
+			values[i] = redactValue(child, keys, replacement, fold)
+		}
+		return NewListValue(&ListValue{Values: values})
+	default:
+		return cloneValue(v)
+	}
+}
@@ -0,0 +1,44 @@
+package types
+
+import "testing"
+
+func TestStructRedact(t *testing.T) {
+	s, err := NewStruct(map[string]interface{}{
+		"username": "alice",
+		"password": "hunter2",
+		"nested":   map[string]interface{}{"token": "secret", "keep": "me"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := map[string]struct{}{"password": {}, "token": {}}
+	redacted := s.Redact(keys, nil)
+
+	if got := redacted.Fields["password"].GetStringValue(); got != "***" {
+		t.Errorf("password = %q, want ***", got)
+	}
+	nested := redacted.Fields["nested"].GetStructValue()
+	if got := nested.Fields["token"].GetStringValue(); got != "***" {
+		t.Errorf("nested.token = %q, want ***", got)
+	}
+	if got := nested.Fields["keep"].GetStringValue(); got != "me" {
+		t.Errorf("nested.keep = %q, want me (untouched)", got)
+	}
+
+	// Original is untouched.
+	if got := s.Fields["password"].GetStringValue(); got != "hunter2" {
+		t.Errorf("original mutated: password = %q, want hunter2", got)
+	}
+}
+
+func TestStructRedactFold(t *testing.T) {
+	s, err := NewStruct(map[string]interface{}{"Password": "hunter2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	redacted := s.RedactFold(map[string]struct{}{"password": {}}, nil)
+	if got := redacted.Fields["Password"].GetStringValue(); got != "***" {
+		t.Errorf("Password = %q, want ***", got)
+	}
+}
@@ -0,0 +1,56 @@
+package types
+
+// StripNulls returns a deep copy of x with every field whose value is a
+// NullValue removed, recursing into nested Structs and ListValues. If
+// pruneEmpty is true, sub-structs that become empty as a result (including
+// sub-structs that were already empty) are themselves removed from their
+// parent, recursively. List elements that are NullValue are removed too,
+// shifting later elements down; there is no position-preserving mode,
+// since a dropped slot has no meaningful placeholder once nulls themselves
+// are what's being discarded.
+func (x *Struct) StripNulls(pruneEmpty bool) *Struct {
+	out := &Struct{Fields: make(map[string]*Value)}
+	for k, v := range x.GetFields() {
+		if isNullValue(v) {
+			continue
+		}
+		sv, isEmptyStruct := stripNullsValue(v, pruneEmpty)
+		if pruneEmpty && isEmptyStruct {
+			continue
+		}
+		out.Fields[k] = sv
+	}
+	return out
+}
+
+// isNullValue reports whether v's kind is Value_NullValue.
+func isNullValue(v *Value) bool {
+	_, ok := v.GetKind().(*Value_NullValue)
+	return ok
+}
+
+// stripNullsValue returns a deep copy of v with nulls stripped, along with
+// whether v is itself a Struct that ended up with no fields.
+func stripNullsValue(v *Value, pruneEmpty bool) (*Value, bool) {
+	switch k := v.GetKind().(type) {
+	case *Value_StructValue:
+		stripped := k.StructValue.StripNulls(pruneEmpty)
+		return NewStructValue(stripped), len(stripped.Fields) == 0
+	case *Value_ListValue:
+		values := k.ListValue.GetValues()
+		out := make([]*Value, 0, len(values))
+		for _, child := range values {
+			if isNullValue(child) {
+				continue
+			}
+			sv, isEmptyStruct := stripNullsValue(child, pruneEmpty)
+			if pruneEmpty && isEmptyStruct {
+				continue
+			}
+			out = append(out, sv)
+		}
+		return NewListValue(&ListValue{Values: out}), false
+	default:
+		return cloneValue(v), false
+	}
+}
@@ -0,0 +1,49 @@
+package types
+
+import "testing"
+
+func TestStructStripNullsTopLevel(t *testing.T) {
+	s, err := NewStruct(map[string]interface{}{"a": nil, "b": "keep"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := s.StripNulls(false)
+	if _, ok := got.Fields["a"]; ok {
+		t.Errorf("a should have been stripped")
+	}
+	if got.Fields["b"].GetStringValue() != "keep" {
+		t.Errorf("b = %v, want keep", got.Fields["b"])
+	}
+}
+
+func TestStructStripNullsNestedAndLists(t *testing.T) {
+	s, err := NewStruct(map[string]interface{}{
+		"list":        []interface{}{1.0, nil, 2.0},
+		"onlyNull":    map[string]interface{}{"x": nil},
+		"mixedNested": map[string]interface{}{"x": nil, "y": 1.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withoutPrune := s.StripNulls(false)
+	list := withoutPrune.Fields["list"].GetListValue().AsSlice()
+	if len(list) != 2 || list[0] != 1.0 || list[1] != 2.0 {
+		t.Errorf("list = %v, want [1 2]", list)
+	}
+	if _, ok := withoutPrune.Fields["onlyNull"]; !ok {
+		t.Errorf("onlyNull should remain (as an empty struct) when pruneEmpty is false")
+	}
+	if got := withoutPrune.Fields["onlyNull"].GetStructValue().Len(); got != 0 {
+		t.Errorf("onlyNull struct should have had its null field stripped, len = %d", got)
+	}
+
+	withPrune := s.StripNulls(true)
+	if _, ok := withPrune.Fields["onlyNull"]; ok {
+		t.Errorf("onlyNull should be pruned entirely when pruneEmpty is true")
+	}
+	mixed := withPrune.Fields["mixedNested"].GetStructValue()
+	if mixed.Len() != 1 || mixed.Fields["y"].AsInterface() != 1.0 {
+		t.Errorf("mixedNested = %v, want {y: 1}", mixed)
+	}
+}
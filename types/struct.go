@@ -3,12 +3,21 @@ package types
 // This file contains extensions to gogo Struct and Value types, addind MarshalJSON/UnmarshalJSON and some other helper functions
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	math "math"
+	"reflect"
+	"sort"
 	strconv "strconv"
+	"strings"
+	"time"
 	"unicode/utf8"
+	"unsafe"
 )
 
 // NewStruct constructs a Struct from a general-purpose Go map.
@@ -39,6 +48,63 @@ func (x *Struct) AsMap() map[string]interface{} {
 	return vs
 }
 
+// Len returns the number of fields in x. It is nil-safe: a nil Struct has
+// length 0.
+func (x *Struct) Len() int {
+	return len(x.GetFields())
+}
+
+// Keys returns the field names of x in sorted order. It is nil-safe,
+// returning an empty (non-nil) slice for a nil or empty Struct.
+func (x *Struct) Keys() []string {
+	fields := x.GetFields()
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Values returns x's field values ordered to match Keys, i.e. sorted by
+// key. It is nil-safe, returning an empty (non-nil) slice for a nil or
+// empty Struct.
+func (x *Struct) Values() []*Value {
+	fields := x.GetFields()
+	keys := x.Keys()
+	values := make([]*Value, 0, len(fields))
+	for _, k := range keys {
+		values = append(values, fields[k])
+	}
+	return values
+}
+
+// GetFold looks up key in x's fields case-insensitively (Unicode simple
+// folding, as used by strings.EqualFold), returning its value and true if
+// found. If multiple keys fold to the same value, which one is returned is
+// unspecified, since map iteration order is randomized; GetFold is only a
+// good fit when callers can assume key casing is inconsistent but not
+// ambiguous. It is nil-safe.
+func (x *Struct) GetFold(key string) (*Value, bool) {
+	for k, v := range x.GetFields() {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// AsMapInto converts x to a general-purpose Go map like AsMap, but writes
+// the result into the caller-provided dst instead of allocating a new map.
+// dst is not cleared first, so any keys it already holds that are not
+// present in x will remain. This lets callers that repeatedly derive the
+// same interface view reuse a single map and avoid per-call allocations.
+func (x *Struct) AsMapInto(dst map[string]interface{}) {
+	for k, v := range x.GetFields() {
+		dst[k] = v.AsInterface()
+	}
+}
+
 // NewValue constructs a Value from a general-purpose Go interface.
 //
 //	╔════════════════════════╤════════════════════════════════════════════╗
@@ -49,12 +115,21 @@ func (x *Struct) AsMap() map[string]interface{} {
 //	║ int, int32, int64      │ stored as NumberValue                      ║
 //	║ uint, uint32, uint64   │ stored as NumberValue                      ║
 //	║ float32, float64       │ stored as NumberValue                      ║
+//	║ time.Duration          │ stored as StringValue; see NewDurationValue║
 //	║ string                 │ stored as StringValue; must be valid UTF-8 ║
 //	║ []byte                 │ stored as StringValue; base64-encoded      ║
 //	║ map[string]interface{} │ stored as StructValue                      ║
 //	║ []interface{}          │ stored as ListValue                        ║
 //	╚════════════════════════╧════════════════════════════════════════════╝
 //
+// A pointer to any of the above types is also accepted: a nil pointer is
+// stored as NullValue, and a non-nil pointer is dereferenced and converted
+// as its pointed-to value, recursing if that value is itself a pointer.
+//
+// map[interface{}]interface{}, as produced by some YAML decoders, is also
+// accepted and stored as a StructValue; each key must be a string or NewValue
+// returns an error.
+//
 // When converting an int64 or uint64 to a NumberValue, numeric precision loss
 // is possible since they are stored as a float64.
 func NewValue(v interface{}) (*Value, error) {
@@ -79,6 +154,8 @@ func NewValue(v interface{}) (*Value, error) {
 		return NewNumberValue(float64(v)), nil
 	case float64:
 		return NewNumberValue(float64(v)), nil
+	case time.Duration:
+		return NewDurationValue(v), nil
 	case string:
 		if !utf8.ValidString(v) {
 			return nil, fmt.Errorf("invalid UTF-8 in string: %q", v)
@@ -99,11 +176,62 @@ func NewValue(v interface{}) (*Value, error) {
 			return nil, err
 		}
 		return NewListValue(v2), nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, mv := range v {
+			sk, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid map key %v (%T): only strings are supported", k, k)
+			}
+			m[sk] = mv
+		}
+		v2, err := NewStruct(m)
+		if err != nil {
+			return nil, err
+		}
+		return NewStructValue(v2), nil
 	default:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return NewNullValue(), nil
+			}
+			return NewValue(rv.Elem().Interface())
+		}
 		return nil, fmt.Errorf("invalid type: %T", v)
 	}
 }
 
+// NewBytesValueEncoding constructs a new StringValue from v, base64-encoded
+// using enc instead of the base64.StdEncoding that NewValue always uses.
+// Use this when exchanging data with systems that expect a different
+// base64 variant, such as base64.RawURLEncoding.
+func NewBytesValueEncoding(v []byte, enc *base64.Encoding) *Value {
+	return NewStringValue(enc.EncodeToString(v))
+}
+
+// AsBytes decodes x, which must be a StringValue, as base64 using enc. If
+// enc is nil, it tries base64.StdEncoding and then base64.RawURLEncoding,
+// returning the first successful decode; this is useful when the sender's
+// base64 variant is not known ahead of time.
+func (x *Value) AsBytes(enc *base64.Encoding) ([]byte, error) {
+	sv, ok := x.GetKind().(*Value_StringValue)
+	if !ok {
+		return nil, fmt.Errorf("AsBytes: Value is not a StringValue")
+	}
+	if enc != nil {
+		return enc.DecodeString(sv.StringValue)
+	}
+	var lastErr error
+	for _, e := range []*base64.Encoding{base64.StdEncoding, base64.RawURLEncoding} {
+		b, err := e.DecodeString(sv.StringValue)
+		if err == nil {
+			return b, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
 // NewNullValue constructs a new null Value.
 func NewNullValue() *Value {
 	return &Value{Kind: &Value_NullValue{NullValue: NullValue_NULL_VALUE}}
@@ -158,7 +286,7 @@ func (x *Value) AsInterface() interface{} {
 		}
 	case *Value_StringValue:
 		if v != nil {
-			return v.StringValue
+			return untagNumericString(v.StringValue)
 		}
 	case *Value_BoolValue:
 		if v != nil {
@@ -176,6 +304,133 @@ func (x *Value) AsInterface() interface{} {
 	return nil
 }
 
+// kindRank orders Value kinds for CompareValues: null < bool < number <
+// string < list < struct. An unset Kind sorts as null.
+func (x *Value) kindRank() int {
+	switch x.GetKind().(type) {
+	case nil, *Value_NullValue:
+		return 0
+	case *Value_BoolValue:
+		return 1
+	case *Value_NumberValue:
+		return 2
+	case *Value_StringValue:
+		return 3
+	case *Value_ListValue:
+		return 4
+	case *Value_StructValue:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// CompareValues returns -1, 0, or 1 depending on whether x sorts before,
+// equal to, or after y, establishing a total order over Values.
+//
+// Values are first ordered by kind using the sequence:
+// null < bool < number < string < list < struct.
+//
+// Within a kind, values are compared as follows:
+//   - bool: false < true.
+//   - number: natural numeric order.
+//   - string: lexicographic byte order.
+//   - list: elements are compared pairwise in order; if all compare
+//     equal, the shorter list sorts first.
+//   - struct: fields are compared by key in sorted order, then by value;
+//     a struct with a field the other lacks sorts after the one missing
+//     it once all common keys compare equal.
+//
+// CompareValues is intended for sort.Slice over ListValue.Values and for
+// producing a canonical ordering, for example before hashing.
+func CompareValues(x, y *Value) int {
+	xr, yr := x.kindRank(), y.kindRank()
+	if xr != yr {
+		return compareInt(xr, yr)
+	}
+	switch xk := x.GetKind().(type) {
+	case *Value_BoolValue:
+		yk := y.GetKind().(*Value_BoolValue)
+		if xk.BoolValue == yk.BoolValue {
+			return 0
+		}
+		if !xk.BoolValue {
+			return -1
+		}
+		return 1
+	case *Value_NumberValue:
+		yk := y.GetKind().(*Value_NumberValue)
+		return compareFloat(xk.NumberValue, yk.NumberValue)
+	case *Value_StringValue:
+		yk := y.GetKind().(*Value_StringValue)
+		if xk.StringValue == yk.StringValue {
+			return 0
+		}
+		if xk.StringValue < yk.StringValue {
+			return -1
+		}
+		return 1
+	case *Value_ListValue:
+		yk := y.GetKind().(*Value_ListValue)
+		xs, ys := xk.ListValue.GetValues(), yk.ListValue.GetValues()
+		for i := 0; i < len(xs) && i < len(ys); i++ {
+			if c := CompareValues(xs[i], ys[i]); c != 0 {
+				return c
+			}
+		}
+		return compareInt(len(xs), len(ys))
+	case *Value_StructValue:
+		yk := y.GetKind().(*Value_StructValue)
+		xf, yf := xk.StructValue.GetFields(), yk.StructValue.GetFields()
+		xKeys, yKeys := sortedKeys(xf), sortedKeys(yf)
+		for i := 0; i < len(xKeys) && i < len(yKeys); i++ {
+			if xKeys[i] != yKeys[i] {
+				if xKeys[i] < yKeys[i] {
+					return -1
+				}
+				return 1
+			}
+			if c := CompareValues(xf[xKeys[i]], yf[yKeys[i]]); c != 0 {
+				return c
+			}
+		}
+		return compareInt(len(xKeys), len(yKeys))
+	default:
+		return 0
+	}
+}
+
+func compareInt(x, y int) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareFloat(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func sortedKeys(m map[string]*Value) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // NewList constructs a ListValue from a general-purpose Go slice.
 // The slice elements are converted using NewValue.
 func NewList(v []interface{}) (*ListValue, error) {
@@ -200,15 +455,65 @@ func (x *ListValue) AsSlice() []interface{} {
 	return vs
 }
 
+// AsSliceInto converts x to a general-purpose Go slice like AsSlice, but
+// reuses dst's storage when it has enough capacity instead of always
+// allocating a new slice. The returned slice should be used in place of dst,
+// since its length is reset to match x.
+func (x *ListValue) AsSliceInto(dst []interface{}) []interface{} {
+	values := x.GetValues()
+	if cap(dst) < len(values) {
+		dst = make([]interface{}, len(values))
+	} else {
+		dst = dst[:len(values)]
+	}
+	for i, v := range values {
+		dst[i] = v.AsInterface()
+	}
+	return dst
+}
+
+// Len returns the number of elements in x. It is nil-safe: a nil
+// ListValue has length 0.
+func (x *ListValue) Len() int {
+	return len(x.GetValues())
+}
+
+// At returns the element at index i, or nil if i is out of range.
+func (x *ListValue) At(i int) *Value {
+	values := x.GetValues()
+	if i < 0 || i >= len(values) {
+		return nil
+	}
+	return values[i]
+}
+
+// Append converts v using NewValue and appends the result to x.Values.
+func (x *ListValue) Append(v interface{}) error {
+	nv, err := NewValue(v)
+	if err != nil {
+		return err
+	}
+	x.Values = append(x.Values, nv)
+	return nil
+}
+
 func (x Value) MarshalJSON() ([]byte, error) {
 	switch v := x.GetKind().(type) {
 	case *Value_NumberValue:
 		if v != nil {
-			return json.Marshal(x.Kind.(*Value_NumberValue).NumberValue)
+			f := v.NumberValue
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				// Preserve encoding/json's error for non-finite values.
+				return json.Marshal(f)
+			}
+			// Use the same 'g'/-1/64 formatting that unmarshal parses
+			// with, so marshal-then-unmarshal is a fixed point for every
+			// finite float64.
+			return strconv.AppendFloat(nil, f, 'g', -1, 64), nil
 		}
 	case *Value_StringValue:
 		if v != nil {
-			return json.Marshal(x.Kind.(*Value_StringValue).StringValue)
+			return marshalJSONStringRaw(untagNumericString(x.Kind.(*Value_StringValue).StringValue))
 		}
 	case *Value_BoolValue:
 		if v != nil {
@@ -230,6 +535,281 @@ func (x *Value) UnmarshalJSON(b []byte) error {
 	return x.unmarshal(b)
 }
 
+// marshalJSONStringRaw encodes s as a quoted JSON string without applying
+// the default HTML escaping that json.Marshal performs on '<', '>', and
+// '&'. Leaving those characters unescaped here lets the outer encode pass
+// (whether that's the default json.Marshal, or a MarshalOptions with
+// EscapeHTML set) apply HTML escaping exactly once, consistently, rather
+// than baking it in unconditionally at this layer.
+func marshalJSONStringRaw(s string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// UnmarshalOptions configures how JSON is decoded into Struct, ListValue,
+// and Value.
+type UnmarshalOptions struct {
+	// CoerceStringNumbers, when set, treats a StringValue whose content
+	// parses cleanly as a number as a NumberValue instead. This is opt-in
+	// because it would otherwise corrupt legitimately numeric-looking
+	// strings, such as ZIP codes.
+	CoerceStringNumbers bool
+
+	// Strict, when set, rejects input with trailing non-whitespace bytes
+	// after a complete top-level JSON value, the same way json.Decoder's
+	// More method would flag it. This catches truncated-then-concatenated
+	// payloads that a plain json.Unmarshal call might otherwise accept
+	// for some inputs.
+	Strict bool
+
+	// Lenient, when set, tolerates JSON5-ish input before decoding: "//"
+	// line comments, "/* */" block comments (neither recognized inside
+	// strings), and a single trailing comma before a closing "]" or "}".
+	// This is opt-in and runs before Strict's trailing-data check, so the
+	// two can be combined.
+	Lenient bool
+
+	// ZeroCopyStrings, when set, decodes JSON strings that contain no
+	// escape sequences by slicing them directly out of the input buffer
+	// passed to Decode or Unmarshal instead of allocating a copy. This
+	// avoids an allocation per string field on large, string-heavy
+	// payloads, but it means the resulting StringValues alias the input
+	// buffer: the caller must not mutate or release that buffer while
+	// the decoded Struct, ListValue, or Value is still in use. Strings
+	// containing escape sequences are always copied, since unescaping
+	// requires building a new string regardless.
+	ZeroCopyStrings bool
+
+	// MaxElements, when positive, caps the total number of Values created
+	// while decoding a single Decode or Unmarshal call, across the whole
+	// tree (not just one level), returning an error instead of continuing
+	// once the cap is exceeded. This bounds memory use against a flat
+	// array with a huge number of elements, which nesting-depth limits
+	// don't catch. Zero means unlimited.
+	MaxElements int
+
+	// InternStrings, when set, deduplicates identical strings (both Struct
+	// keys and StringValue contents) through a per-decode intern table
+	// instead of allocating a new string for each occurrence. This reduces
+	// memory for payloads that repeat the same keys or string values many
+	// times, such as logs. Interned strings remain valid for as long as the
+	// decoded result is used; they only become reusable again when the
+	// Decoder producing them is Reset (same lifetime as arena-allocated
+	// Values).
+	InternStrings bool
+
+	// PreserveNumbersAsStrings, when set, decodes every JSON number token
+	// verbatim into a tagged StringValue instead of a float64 NumberValue,
+	// avoiding any precision loss for arbitrary-precision decimals (e.g.
+	// money amounts) that don't survive a float64 round trip. The tag is
+	// an internal marker recognized by MarshalPreservingNumbers, which
+	// must be used to marshal the result back to the original unquoted
+	// number; general-purpose accessors that don't know about the tag
+	// (AsInterface, AsMap, the ordinary MarshalJSON) strip it and see the
+	// verbatim number text as a plain string instead.
+	PreserveNumbersAsStrings bool
+
+	// DecodeHook, when set, is called before the default decoding of
+	// each scalar JSON value (string, number, bool, or null) encountered
+	// anywhere in the tree, with its dotted/bracketed path (as produced
+	// by joinPath/fmt.Sprintf("%s[%d]", ...)) and raw JSON bytes. If it
+	// returns ok == true, its returned Value replaces the default
+	// decoding for that position instead of falling through to the usual
+	// type switch; returning ok == false (with a nil error) leaves the
+	// default decoding unchanged. Structs and lists are always decoded
+	// structurally and are not themselves offered to DecodeHook, only
+	// their scalar leaves.
+	DecodeHook func(path string, raw json.RawMessage) (*Value, bool, error)
+
+	// MaxFields, when positive, caps the number of fields any single
+	// Struct object in the input may have, checked as soon as that
+	// object's field count is known, before its values are decoded. This
+	// rejects a huge flat object up front, complementing MaxElements,
+	// which only catches excess size once the whole tree is walked. Zero
+	// means unlimited.
+	MaxFields int
+
+	// MaxStringLen, when positive, caps the length in bytes of any
+	// decoded string, whether a Struct key or a StringValue, checked as
+	// soon as that string is unquoted. Zero means unlimited.
+	MaxStringLen int
+
+	// PrecisionLossHook, when set, is called with the dotted/bracketed
+	// path and raw token of every decoded integer-valued JSON number
+	// whose value can't be represented exactly as a float64, i.e. where
+	// parsing it as an int64 and truncating the decoded float64 back to
+	// an int64 disagree. This is opt-in diagnostics for auditing which
+	// fields of a payload are at risk under the default float64-backed
+	// NumberValue representation; use PreserveNumbersAsStrings instead if
+	// you need to avoid the loss altogether rather than just observe it.
+	PrecisionLossHook func(path, raw string)
+
+	// ReplaceInvalidUTF8, when set, replaces invalid UTF-8 byte sequences
+	// in decoded Struct keys and StringValues with the Unicode replacement
+	// character instead of failing the decode. This mainly matters when
+	// ZeroCopyStrings is also set: its zero-copy path aliases bytes
+	// straight out of the input buffer, skipping the UTF-8 sanitization
+	// encoding/json normally performs while unescaping, so invalid bytes
+	// in the raw input would otherwise end up inside a Go string without
+	// ever being rejected. By default (false), such input is rejected
+	// with an error, matching NewStruct and NewValue's behavior for
+	// manually constructed Structs and Values.
+	ReplaceInvalidUTF8 bool
+}
+
+// validateUTF8 returns s if it is valid UTF-8. Otherwise, if d allows
+// replacement, it returns s with invalid byte sequences replaced by the
+// Unicode replacement character; if not, it returns an error naming what
+// failed validation (e.g. "key" or "string").
+func validateUTF8(d *Decoder, what, s string) (string, error) {
+	if utf8.ValidString(s) {
+		return s, nil
+	}
+	if d != nil && d.Options.ReplaceInvalidUTF8 {
+		return strings.ToValidUTF8(s, string(utf8.RuneError)), nil
+	}
+	return "", fmt.Errorf("invalid UTF-8 in %s: %q", what, s)
+}
+
+// Unmarshal decodes data into x, which must be a *Value, *Struct, or
+// *ListValue, according to the options in o.
+func (o UnmarshalOptions) Unmarshal(data []byte, x interface{}) error {
+	d := &Decoder{Options: o}
+	return d.Decode(data, x)
+}
+
+// A Decoder decodes JSON into Values, satisfying nested Value allocations
+// from a reusable arena instead of the heap. This amortizes the many small
+// &Value{} allocations that ListValue and Struct decoding would otherwise
+// make, which helps high-throughput decoding of similarly-shaped payloads.
+//
+// A Decoder is not safe for concurrent use. Values produced by a Decoder
+// must not be accessed after the Decoder is Reset, since Reset makes their
+// backing memory available for reuse by later Decode calls.
+type Decoder struct {
+	// Options controls decoding behavior, such as whether numeric strings
+	// are coerced to NumberValues.
+	Options UnmarshalOptions
+
+	arena  []Value
+	next   int
+	count  int
+	intern map[string]string
+	ctx    context.Context
+}
+
+// NewDecoder returns a Decoder with an empty arena.
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// Reset discards all Values previously produced by d, making their backing
+// memory available for reuse by subsequent Decode calls. Callers must not
+// retain or access Values obtained from d after calling Reset.
+func (d *Decoder) Reset() {
+	d.next = 0
+	d.count = 0
+	d.intern = nil
+}
+
+// internString returns a canonical copy of s, allocating an entry in d's
+// intern table the first time s is seen during this decode and returning
+// the existing entry on subsequent occurrences. It is only called when
+// Options.InternStrings is set.
+func (d *Decoder) internString(s string) string {
+	if d.intern == nil {
+		d.intern = make(map[string]string)
+	}
+	if existing, ok := d.intern[s]; ok {
+		return existing
+	}
+	d.intern[s] = s
+	return s
+}
+
+func (d *Decoder) alloc() *Value {
+	if d.next >= len(d.arena) {
+		n := 64
+		if len(d.arena) > 0 {
+			n = len(d.arena) * 2
+		}
+		d.arena = make([]Value, n)
+		d.next = 0
+	}
+	v := &d.arena[d.next]
+	*v = Value{}
+	d.next++
+	return v
+}
+
+// Decode unmarshals data into x, which must be a *Value, *Struct, or
+// *ListValue, using d's arena to satisfy any nested Value allocations.
+func (d *Decoder) Decode(data []byte, x interface{}) error {
+	data = stripBOM(data)
+	if d.Options.Lenient {
+		data = stripCommentsAndTrailingCommas(data)
+	}
+	if d.Options.Strict {
+		if err := checkNoTrailingData(data); err != nil {
+			return err
+		}
+	}
+	switch x := x.(type) {
+	case *Value:
+		return x.unmarshalWith(data, d, "")
+	case *Struct:
+		return x.unmarshalWith(data, d, "")
+	case *ListValue:
+		return x.unmarshalWith(data, d, "")
+	default:
+		return fmt.Errorf("types: Decode: unsupported type %T", x)
+	}
+}
+
+// checkContext reports d's context error, if d was built with one and it has
+// been canceled or timed out. It is called at every Struct field, ListValue
+// element, and Value encountered during decoding, so a canceled context is
+// noticed promptly rather than only after the whole payload has been walked.
+func (d *Decoder) checkContext() error {
+	if d != nil && d.ctx != nil {
+		if err := d.ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeContext reads all of r and decodes it into x, which must be a
+// *Value, *Struct, or *ListValue, the same way Decoder.Decode does, except
+// that it checks ctx at every Struct field, ListValue element, and Value it
+// visits, returning ctx.Err() as soon as it notices cancellation instead of
+// continuing to decode the rest of a large, already-buffered payload after
+// the caller has given up on it. Note that the initial read from r, and the
+// outermost json.Unmarshal call used to split an object or array into its
+// immediate children, still run to completion uninterrupted; cancellation
+// is only honored between those steps, not in the middle of either.
+func DecodeContext(ctx context.Context, r io.Reader, x interface{}) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d := &Decoder{ctx: ctx}
+	return d.Decode(data, x)
+}
+
+// MarshalJSON encodes x's Fields as a flat JSON object. A nil Fields map
+// marshals identically to a non-nil empty one, so a zero Struct, a
+// Struct{Fields: map[string]*Value{}}, and a Struct unmarshaled from "{}"
+// all produce "{}" and, per the generated Equal, compare proto.Equal to
+// one another.
 func (x Struct) MarshalJSON() ([]byte, error) {
 	if x.Fields == nil {
 		return json.Marshal(map[string]*Value{})
@@ -252,39 +832,181 @@ func (x *ListValue) UnmarshalJSON(b []byte) error {
 	return x.unmarshal(b)
 }
 
+// unmarshal decodes inputValue into x. Rather than probing the type by
+// attempting to decode the payload as a list and then as a map (parsing
+// list/object payloads twice), it peeks the first non-space byte to pick
+// the right path in a single pass.
 func (x *Value) unmarshal(inputValue json.RawMessage) error {
-	ivStr := string(inputValue)
-	if ivStr == "null" {
+	return x.unmarshalWith(stripBOM(inputValue), nil, "")
+}
+
+// errAt formats an error for a decode failure at path, producing messages
+// like "at a.b[2].c: unrecognized type for Value ...". At the root, path is
+// "" and the "at ...: " prefix is omitted.
+func errAt(path, format string, args ...interface{}) error {
+	if path == "" {
+		return fmt.Errorf(format, args...)
+	}
+	return fmt.Errorf("at %s: "+format, append([]interface{}{path}, args...)...)
+}
+
+// unmarshalWith is like unmarshal, but satisfies nested Value allocations
+// from d when it is non-nil instead of the heap, and reports errors with
+// path prefixed to them for diagnosability in deeply nested payloads.
+func (x *Value) unmarshalWith(inputValue json.RawMessage, d *Decoder, path string) error {
+	if err := d.checkContext(); err != nil {
+		return err
+	}
+	v := bytes.TrimSpace(inputValue)
+	if len(v) == 0 {
+		return errAt(path, "unrecognized type for Value %q", inputValue)
+	}
+	if v[0] != '{' && v[0] != '[' && d != nil && d.Options.DecodeHook != nil {
+		hv, ok, err := d.Options.DecodeHook(path, v)
+		if err != nil {
+			return errAt(path, "decode hook: %v", err)
+		}
+		if ok {
+			if hv == nil {
+				hv = &Value{}
+			}
+			x.Kind = hv.Kind
+			return nil
+		}
+	}
+	switch v[0] {
+	case 'n':
+		if string(v) != "null" {
+			return errAt(path, "unrecognized type for Value %q", v)
+		}
 		x.Kind = &Value_NullValue{}
-	} else if v, err := strconv.ParseFloat(ivStr, 0); err == nil {
-		x.Kind = &Value_NumberValue{NumberValue: v}
-	} else if v, err := unquote(ivStr); err == nil {
-		x.Kind = &Value_StringValue{StringValue: v}
-	} else if v, err := strconv.ParseBool(ivStr); err == nil {
-		x.Kind = &Value_BoolValue{BoolValue: v}
-	} else if err := json.Unmarshal(inputValue, &[]json.RawMessage{}); err == nil {
+		return nil
+	case 't', 'f':
+		// Go's strconv.ParseBool is looser than JSON, accepting "1", "0",
+		// "t", "T", and similar single-letter forms; require the exact
+		// JSON literals instead.
+		var b bool
+		switch string(v) {
+		case "true":
+			b = true
+		case "false":
+			b = false
+		default:
+			return errAt(path, "unrecognized type for Value %q", v)
+		}
+		x.Kind = &Value_BoolValue{BoolValue: b}
+		return nil
+	case '"':
+		var s string
+		var err error
+		if d != nil && d.Options.ZeroCopyStrings {
+			if zc, ok := zeroCopyUnquote(v); ok {
+				s = zc
+			} else {
+				s, err = unquote(string(v))
+			}
+		} else {
+			s, err = unquote(string(v))
+		}
+		if err != nil {
+			return errAt(path, "unrecognized type for Value %q", v)
+		}
+		s, err = validateUTF8(d, "string", s)
+		if err != nil {
+			return errAt(path, "%v", err)
+		}
+		if d != nil && d.Options.MaxStringLen > 0 && len(s) > d.Options.MaxStringLen {
+			return errAt(path, "string exceeds max string length (%d)", d.Options.MaxStringLen)
+		}
+		if d != nil && d.Options.CoerceStringNumbers {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				x.Kind = &Value_NumberValue{NumberValue: f}
+				return nil
+			}
+		}
+		if d != nil && d.Options.InternStrings {
+			s = d.internString(s)
+		}
+		x.Kind = &Value_StringValue{StringValue: s}
+		return nil
+	case '[':
 		lv := &ListValue{}
 		x.Kind = &Value_ListValue{ListValue: lv}
-		return lv.unmarshal(inputValue)
-	} else if err := json.Unmarshal(inputValue, &map[string]json.RawMessage{}); err == nil {
+		return lv.unmarshalWith(v, d, path)
+	case '{':
 		sv := &Struct{}
 		x.Kind = &Value_StructValue{StructValue: sv}
-		return sv.unmarshal(inputValue)
-	} else {
-		return fmt.Errorf("unrecognized type for Value %q", ivStr)
+		return sv.unmarshalWith(v, d, path)
+	default:
+		if !isJSONNumber(v) {
+			return errAt(path, "unrecognized type for Value %q", v)
+		}
+		if d != nil && d.Options.PreserveNumbersAsStrings {
+			x.Kind = &Value_StringValue{StringValue: numericTagPrefix + string(v)}
+			return nil
+		}
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return errAt(path, "unrecognized type for Value %q", v)
+		}
+		if d != nil && d.Options.PrecisionLossHook != nil {
+			if i, err := strconv.ParseInt(string(v), 10, 64); err == nil && int64(f) != i {
+				d.Options.PrecisionLossHook(path, string(v))
+			}
+		}
+		x.Kind = &Value_NumberValue{NumberValue: f}
+		return nil
+	}
+}
+
+// jsonRootKind peeks the first non-space byte of data and returns a short
+// human-readable name for the JSON value it introduces ("object", "array",
+// "string", "number", "boolean", or "null"), or "" if data is empty or its
+// root kind can't be determined from the leading byte alone. It is used to
+// reject mismatched roots (e.g. an array fed to Struct.UnmarshalJSON) with a
+// clear error before the generic decode produces a confusing one.
+func jsonRootKind(data []byte) string {
+	v := bytes.TrimSpace(data)
+	if len(v) == 0 {
+		return ""
+	}
+	switch v[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	default:
+		return "number"
 	}
-	return nil
 }
 
 func (x *ListValue) unmarshal(inputValue json.RawMessage) error {
+	return x.unmarshalWith(stripBOM(inputValue), nil, "")
+}
+
+func (x *ListValue) unmarshalWith(inputValue json.RawMessage, d *Decoder, path string) error {
+	if kind := jsonRootKind(inputValue); kind != "" && kind != "array" {
+		return errAt(path, "cannot unmarshal JSON %s into ListValue", kind)
+	}
 	var s []json.RawMessage
 	if err := json.Unmarshal(inputValue, &s); err != nil {
-		return fmt.Errorf("bad ListValue: %v", err)
+		return errAt(path, "bad ListValue: %v", err)
 	}
 	x.Values = make([]*Value, len(s))
 	for i, sv := range s {
-		x.Values[i] = &Value{}
-		if err := x.Values[i].unmarshal(sv); err != nil {
+		elemPath := fmt.Sprintf("%s[%d]", path, i)
+		v, err := newValueChecked(d, elemPath)
+		if err != nil {
+			return err
+		}
+		x.Values[i] = v
+		if err := x.Values[i].unmarshalWith(sv, d, elemPath); err != nil {
 			return err
 		}
 	}
@@ -292,23 +1014,152 @@ func (x *ListValue) unmarshal(inputValue json.RawMessage) error {
 }
 
 func (x *Struct) unmarshal(inputValue json.RawMessage) error {
+	return x.unmarshalWith(stripBOM(inputValue), nil, "")
+}
+
+func (x *Struct) unmarshalWith(inputValue json.RawMessage, d *Decoder, path string) error {
+	if kind := jsonRootKind(inputValue); kind != "" && kind != "object" {
+		return errAt(path, "cannot unmarshal JSON %s into Struct", kind)
+	}
 	var m map[string]json.RawMessage
 	if err := json.Unmarshal(inputValue, &m); err != nil {
-		return fmt.Errorf("bad StructValue: %v", err)
+		return errAt(path, "bad StructValue: %v", err)
+	}
+	if d != nil && d.Options.MaxFields > 0 && len(m) > d.Options.MaxFields {
+		return errAt(path, "exceeded max fields (%d) while unmarshaling", d.Options.MaxFields)
 	}
 	x.Fields = make(map[string]*Value)
 	for k, jv := range m {
-		pv := &Value{}
-		if err := pv.unmarshal(jv); err != nil {
-			return fmt.Errorf("bad value in StructValue for key %q: %v", k, err)
+		k, err := validateUTF8(d, "key", k)
+		if err != nil {
+			return errAt(path, "%v", err)
+		}
+		if d != nil && d.Options.MaxStringLen > 0 && len(k) > d.Options.MaxStringLen {
+			return errAt(path, "key %q exceeds max string length (%d)", k, d.Options.MaxStringLen)
+		}
+		fieldPath := joinPath(path, k)
+		pv, err := newValueChecked(d, fieldPath)
+		if err != nil {
+			return err
+		}
+		if err := pv.unmarshalWith(jv, d, fieldPath); err != nil {
+			return err
+		}
+		if d != nil && d.Options.InternStrings {
+			k = d.internString(k)
 		}
 		x.Fields[k] = pv
 	}
 	return nil
 }
 
+// newValue returns a Value allocated from d's arena, or from the heap if d
+// is nil.
+func newValue(d *Decoder) *Value {
+	if d == nil {
+		return &Value{}
+	}
+	return d.alloc()
+}
+
+// newValueChecked is like newValue, but first enforces d.Options.MaxElements,
+// if set, against the running count of Values allocated for this decode.
+// path is used to give the resulting error the same location context as
+// other unmarshal errors.
+func newValueChecked(d *Decoder, path string) (*Value, error) {
+	if d != nil && d.Options.MaxElements > 0 {
+		d.count++
+		if d.count > d.Options.MaxElements {
+			return nil, errAt(path, "exceeded max elements (%d) while unmarshaling", d.Options.MaxElements)
+		}
+	}
+	return newValue(d), nil
+}
+
+// checkNoTrailingData returns an error if data contains anything beyond a
+// single complete JSON value and optional trailing whitespace.
+func checkNoTrailingData(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("trailing data after JSON value")
+	}
+	return nil
+}
+
+// isJSONNumber reports whether v matches the JSON number grammar exactly:
+// an optional '-', then either "0" or a non-zero digit followed by more
+// digits, optionally followed by a '.' and one or more digits, optionally
+// followed by an exponent ('e' or 'E', optional sign, one or more
+// digits). This rejects tokens strconv.ParseFloat would otherwise accept
+// but JSON does not, such as "NaN", "Infinity", "+1", or "01".
+func isJSONNumber(v []byte) bool {
+	i := 0
+	n := len(v)
+	if i < n && v[i] == '-' {
+		i++
+	}
+	if i >= n {
+		return false
+	}
+	if v[i] == '0' {
+		i++
+	} else if v[i] >= '1' && v[i] <= '9' {
+		i++
+		for i < n && v[i] >= '0' && v[i] <= '9' {
+			i++
+		}
+	} else {
+		return false
+	}
+	if i < n && v[i] == '.' {
+		i++
+		start := i
+		for i < n && v[i] >= '0' && v[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	if i < n && (v[i] == 'e' || v[i] == 'E') {
+		i++
+		if i < n && (v[i] == '+' || v[i] == '-') {
+			i++
+		}
+		start := i
+		for i < n && v[i] >= '0' && v[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	return i == n
+}
+
 func unquote(s string) (string, error) {
 	var ret string
 	err := json.Unmarshal([]byte(s), &ret)
 	return ret, err
 }
+
+// zeroCopyUnquote returns the contents of the quoted JSON string literal v
+// (which must begin and end with '"') as a string that aliases v's backing
+// array, without allocating or copying. It succeeds only when v contains no
+// backslash escapes, since unescaping requires building new bytes anyway;
+// callers should fall back to unquote in that case. The returned string is
+// only valid as long as the buffer v was sliced from remains unmodified.
+func zeroCopyUnquote(v []byte) (string, bool) {
+	if len(v) < 2 || v[0] != '"' || v[len(v)-1] != '"' {
+		return "", false
+	}
+	inner := v[1 : len(v)-1]
+	if bytes.IndexByte(inner, '\\') >= 0 {
+		return "", false
+	}
+	return *(*string)(unsafe.Pointer(&inner)), true
+}
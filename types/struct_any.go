@@ -0,0 +1,18 @@
+package types
+
+// ToAny packs x into a google.protobuf.Any with the correct type URL for
+// Struct, the common case of carrying a Struct through a generic envelope.
+func (x *Struct) ToAny() (*Any, error) {
+	return MarshalAny(x)
+}
+
+// StructFromAny unpacks a Struct previously packed by ToAny (or any other
+// Any whose contents are a google.protobuf.Struct) from a. It returns an
+// error if a does not contain a Struct.
+func StructFromAny(a *Any) (*Struct, error) {
+	var s Struct
+	if err := UnmarshalAny(a, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,38 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructToAnyRoundTrip(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{"a": 1.0, "b": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := s.ToAny()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := types.StructFromAny(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(s, got) {
+		t.Errorf("StructFromAny(ToAny(s)) = %v, want %v", got, s)
+	}
+}
+
+func TestStructFromAnyWrongType(t *testing.T) {
+	a, err := types.MarshalAny(&types.BoolValue{Value: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := types.StructFromAny(a); err == nil {
+		t.Error("StructFromAny() of a BoolValue Any = nil error, want error")
+	}
+}
@@ -0,0 +1,45 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+// TestStructEmptyRepresentationsAreEquivalent guards against a regression
+// where a zero Struct, a Struct with a non-nil but empty Fields map, and a
+// Struct unmarshaled from "{}" would marshal or compare differently.
+func TestStructEmptyRepresentationsAreEquivalent(t *testing.T) {
+	zero := &types.Struct{}
+	emptyMap := &types.Struct{Fields: map[string]*types.Value{}}
+	var unmarshaled types.Struct
+	if err := unmarshaled.UnmarshalJSON([]byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	structs := map[string]*types.Struct{
+		"zero":        zero,
+		"emptyMap":    emptyMap,
+		"unmarshaled": &unmarshaled,
+	}
+	for name, s := range structs {
+		b, err := s.MarshalJSON()
+		if err != nil {
+			t.Fatalf("%s: MarshalJSON() error = %v", name, err)
+		}
+		if want := `{}`; string(b) != want {
+			t.Errorf("%s: MarshalJSON() = %s, want %s", name, b, want)
+		}
+	}
+
+	if !proto.Equal(zero, emptyMap) {
+		t.Error("zero Struct is not proto.Equal to an empty-map Struct")
+	}
+	if !proto.Equal(zero, &unmarshaled) {
+		t.Error("zero Struct is not proto.Equal to an unmarshaled empty Struct")
+	}
+	if !proto.Equal(emptyMap, &unmarshaled) {
+		t.Error("empty-map Struct is not proto.Equal to an unmarshaled empty Struct")
+	}
+}
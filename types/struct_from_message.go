@@ -0,0 +1,173 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	strconv "strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+type wellKnownType interface {
+	XXX_WellKnownType() string
+}
+
+// StructFromMessage builds a Struct mirroring m's JSON representation using
+// proto reflection, the inverse of PopulateMessage. It gives generic tooling
+// (filtering, diffing) a uniform dynamic view over any message without
+// round-tripping through JSON bytes.
+//
+// Nested messages and repeated fields are converted recursively. Map
+// fields are converted to a Struct keyed by the map key (formatted as a
+// string for non-string key types). Well-known wrapper types (DoubleValue,
+// Int32Value, StringValue, and so on) are unwrapped to their bare scalar
+// rather than nested as an object. Unset (nil) message fields are omitted.
+// The other well-known types (Timestamp, Duration, Any) are not specially
+// handled and are converted field-by-field like any other message. Oneofs
+// are not supported: for a message with a oneof field, set or not,
+// StructFromMessage returns an error.
+func StructFromMessage(m proto.Message) (*Struct, error) {
+	rv := reflect.ValueOf(m)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, fmt.Errorf("StructFromMessage: m must be a non-nil pointer to a message")
+	}
+	return structFromReflect(rv.Elem())
+}
+
+func structFromReflect(target reflect.Value) (*Struct, error) {
+	sprops := proto.GetProperties(target.Type())
+	x := &Struct{Fields: make(map[string]*Value)}
+	for i := 0; i < target.NumField(); i++ {
+		if strings.HasPrefix(target.Type().Field(i).Name, "XXX_") {
+			continue
+		}
+		prop := sprops.Prop[i]
+		if prop == nil || prop.OrigName == "" {
+			continue
+		}
+		field := target.Field(i)
+		var (
+			v   *Value
+			err error
+		)
+		switch {
+		case field.Kind() == reflect.Map:
+			v, err = structValueFromMapReflect(field)
+		case prop.Repeated:
+			v, err = listValueFromReflect(field)
+		default:
+			v, err = scalarOrMessageFromReflect(field)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %v", prop.OrigName, err)
+		}
+		if v == nil {
+			continue
+		}
+		jsonName := prop.JSONName
+		if jsonName == "" {
+			jsonName = prop.OrigName
+		}
+		x.Fields[jsonName] = v
+	}
+	return x, nil
+}
+
+func listValueFromReflect(field reflect.Value) (*Value, error) {
+	lv := &ListValue{Values: make([]*Value, field.Len())}
+	for i := 0; i < field.Len(); i++ {
+		v, err := scalarOrMessageFromReflect(field.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %v", i, err)
+		}
+		lv.Values[i] = v
+	}
+	return NewListValue(lv), nil
+}
+
+// structValueFromMapReflect converts a proto map field to a StructValue
+// keyed by the map's keys, formatted as strings. Map values are converted
+// the same way a singular field of that type would be.
+func structValueFromMapReflect(field reflect.Value) (*Value, error) {
+	sv := &Struct{Fields: make(map[string]*Value, field.Len())}
+	iter := field.MapRange()
+	for iter.Next() {
+		key, err := mapKeyToString(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		v, err := scalarOrMessageFromReflect(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %v", key, err)
+		}
+		if v == nil {
+			continue
+		}
+		sv.Fields[key] = v
+	}
+	return NewStructValue(sv), nil
+}
+
+// mapKeyToString formats a proto map key (string, integer, or bool, the
+// only key types protobuf allows) as a string, matching how jsonpb renders
+// non-string map keys as JSON object keys.
+func mapKeyToString(key reflect.Value) (string, error) {
+	switch key.Kind() {
+	case reflect.String:
+		return key.String(), nil
+	case reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	case reflect.Bool:
+		return strconv.FormatBool(key.Bool()), nil
+	default:
+		return "", fmt.Errorf("unsupported map key kind %s", key.Kind())
+	}
+}
+
+func scalarOrMessageFromReflect(field reflect.Value) (*Value, error) {
+	if field.Kind() != reflect.Ptr {
+		return scalarValueFromReflect(field)
+	}
+	if field.IsNil() {
+		return nil, nil
+	}
+	elem := field.Elem()
+	if wkt, ok := field.Interface().(wellKnownType); ok {
+		switch wkt.XXX_WellKnownType() {
+		case "DoubleValue", "FloatValue", "Int64Value", "UInt64Value",
+			"Int32Value", "UInt32Value", "BoolValue", "StringValue", "BytesValue":
+			return scalarValueFromReflect(elem.Field(0))
+		}
+	}
+	sv, err := structFromReflect(elem)
+	if err != nil {
+		return nil, err
+	}
+	return NewStructValue(sv), nil
+}
+
+func scalarValueFromReflect(field reflect.Value) (*Value, error) {
+	switch field.Kind() {
+	case reflect.Bool:
+		return NewBoolValue(field.Bool()), nil
+	case reflect.String:
+		return NewStringValue(field.String()), nil
+	case reflect.Int32, reflect.Int64:
+		return NewNumberValue(float64(field.Int())), nil
+	case reflect.Uint32, reflect.Uint64:
+		return NewNumberValue(float64(field.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return NewNumberValue(field.Float()), nil
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.Uint8 {
+			return nil, fmt.Errorf("unsupported field type %s", field.Type())
+		}
+		return NewStringValue(base64.StdEncoding.EncodeToString(field.Bytes())), nil
+	default:
+		return nil, fmt.Errorf("unsupported field kind %s", field.Kind())
+	}
+}
@@ -0,0 +1,99 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructFromMessageNestedAndRepeated(t *testing.T) {
+	p := &testPerson{
+		Name:    "Ada",
+		Age:     36,
+		Active:  true,
+		Address: &testAddress{City: "London"},
+		Tags:    []string{"math", "computing"},
+	}
+
+	x, err := types.StructFromMessage(p)
+	if err != nil {
+		t.Fatalf("StructFromMessage: %v", err)
+	}
+
+	if got := x.Fields["name"].AsInterface(); got != "Ada" {
+		t.Errorf("name = %v, want Ada", got)
+	}
+	if got := x.Fields["age"].AsInterface(); got != 36.0 {
+		t.Errorf("age = %v, want 36", got)
+	}
+	if got := x.Fields["active"].AsInterface(); got != true {
+		t.Errorf("active = %v, want true", got)
+	}
+	if got := x.Fields["address"].GetStructValue().Fields["city"].AsInterface(); got != "London" {
+		t.Errorf("address.city = %v, want London", got)
+	}
+	tags := x.Fields["tags"].GetListValue().GetValues()
+	if len(tags) != 2 || tags[0].AsInterface() != "math" || tags[1].AsInterface() != "computing" {
+		t.Errorf("tags = %v, want [math computing]", tags)
+	}
+}
+
+func TestStructFromMessageOmitsNilMessageField(t *testing.T) {
+	p := &testPerson{Name: "Grace"}
+	x, err := types.StructFromMessage(p)
+	if err != nil {
+		t.Fatalf("StructFromMessage: %v", err)
+	}
+	if _, ok := x.Fields["address"]; ok {
+		t.Errorf("Fields[address] present for nil Address, want omitted")
+	}
+}
+
+// testLabels is a hand-written stand-in for a generated message with a
+// map field, tagged the way protoc-gen-gogo would tag it.
+type testLabels struct {
+	Tags map[string]string `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *testLabels) Reset()         { *m = testLabels{} }
+func (m *testLabels) String() string { return "" }
+func (m *testLabels) ProtoMessage()  {}
+
+func TestStructFromMessageMapField(t *testing.T) {
+	p := &testLabels{Tags: map[string]string{"a": "1", "b": "2"}}
+
+	x, err := types.StructFromMessage(p)
+	if err != nil {
+		t.Fatalf("StructFromMessage: %v", err)
+	}
+	tags := x.Fields["tags"].GetStructValue()
+	if tags == nil {
+		t.Fatal("Fields[tags] is not a StructValue")
+	}
+	if got := tags.Fields["a"].AsInterface(); got != "1" {
+		t.Errorf("tags[a] = %v, want 1", got)
+	}
+	if got := tags.Fields["b"].AsInterface(); got != "2" {
+		t.Errorf("tags[b] = %v, want 2", got)
+	}
+}
+
+func TestStructFromMessagePopulateMessageRoundTrip(t *testing.T) {
+	orig := &testPerson{
+		Name:    "Ada",
+		Age:     36,
+		Address: &testAddress{City: "London"},
+		Tags:    []string{"math"},
+	}
+	x, err := types.StructFromMessage(orig)
+	if err != nil {
+		t.Fatalf("StructFromMessage: %v", err)
+	}
+	var got testPerson
+	if err := types.PopulateMessage(&got, x); err != nil {
+		t.Fatalf("PopulateMessage: %v", err)
+	}
+	if got.Name != orig.Name || got.Age != orig.Age || got.Address.City != orig.Address.City {
+		t.Errorf("round trip mismatch: got %+v, want %+v", got, orig)
+	}
+}
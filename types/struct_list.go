@@ -0,0 +1,21 @@
+package types
+
+// GetStructList returns the Structs in the ListValue at key, or false if
+// key is absent, not a ListValue, or contains any element that isn't
+// itself a Struct.
+func (x *Struct) GetStructList(key string) ([]*Struct, bool) {
+	lv, ok := x.GetFields()[key].GetKind().(*Value_ListValue)
+	if !ok || lv == nil {
+		return nil, false
+	}
+	values := lv.ListValue.GetValues()
+	out := make([]*Struct, len(values))
+	for i, v := range values {
+		sv, ok := v.GetKind().(*Value_StructValue)
+		if !ok || sv == nil {
+			return nil, false
+		}
+		out[i] = sv.StructValue
+	}
+	return out, true
+}
@@ -0,0 +1,56 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructGetStructListHappyPath(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": "1"},
+			map[string]interface{}{"id": "2"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := s.GetStructList("items")
+	if !ok {
+		t.Fatal("GetStructList() ok = false, want true")
+	}
+	if len(got) != 2 || got[0].Fields["id"].AsInterface() != "1" {
+		t.Errorf("GetStructList() = %v, want 2 structs starting with id 1", got)
+	}
+}
+
+func TestStructGetStructListNonStructElement(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": "1"},
+			"not a struct",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := s.GetStructList("items"); ok {
+		t.Error("GetStructList() ok = true, want false")
+	}
+}
+
+func TestStructGetStructListAbsentOrWrongType(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{"a": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.GetStructList("missing"); ok {
+		t.Error("GetStructList(missing) ok = true, want false")
+	}
+	if _, ok := s.GetStructList("a"); ok {
+		t.Error("GetStructList(a) ok = true, want false")
+	}
+}
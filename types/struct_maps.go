@@ -0,0 +1,30 @@
+package types
+
+import "fmt"
+
+// ToValueMap returns a nil-safe copy of x's Fields, for populating a
+// proto map<string, Value> field directly from a Struct.
+func (x *Struct) ToValueMap() map[string]*Value {
+	fields := x.GetFields()
+	out := make(map[string]*Value, len(fields))
+	for k, v := range fields {
+		out[k] = v
+	}
+	return out
+}
+
+// ToStringMap returns x's Fields as a map[string]string, for populating a
+// proto map<string, string> field directly from a Struct. It returns an
+// error naming the offending key if any field is not a StringValue.
+func (x *Struct) ToStringMap() (map[string]string, error) {
+	fields := x.GetFields()
+	out := make(map[string]string, len(fields))
+	for k, v := range fields {
+		sv, ok := v.GetKind().(*Value_StringValue)
+		if !ok {
+			return nil, fmt.Errorf("types: ToStringMap: field %q is not a string", k)
+		}
+		out[k] = sv.StringValue
+	}
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructToValueMap(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{"a": 1.0, "b": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := s.ToValueMap()
+	if got := m["a"].GetNumberValue(); got != 1 {
+		t.Errorf("m[a] = %v, want 1", got)
+	}
+	if got := m["b"].GetStringValue(); got != "x" {
+		t.Errorf("m[b] = %q, want x", got)
+	}
+}
+
+func TestStructToStringMap(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{"a": "x", "b": "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := s.ToStringMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"a": "x", "b": "y"}; !mapsEqual(m, want) {
+		t.Errorf("ToStringMap() = %v, want %v", m, want)
+	}
+}
+
+func TestStructToStringMapNonStringError(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{"a": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.ToStringMap(); err == nil {
+		t.Error("ToStringMap() = nil error, want error")
+	}
+}
+
+func mapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package types
+
+// RenameKeys returns a deep copy of x with its top-level keys renamed
+// according to mapping: a field stored under a key present in mapping is
+// moved to mapping[key], and any key not in mapping is left unchanged. If
+// recurse is true, the same renaming is applied inside every nested
+// Struct, at any depth, using the same mapping.
+//
+// If renaming causes two keys to collide on the same target name, the
+// target holds whichever of the colliding source fields Go's map iteration
+// happens to visit last; since that order is unspecified, callers whose
+// mapping can collide should not rely on which one wins.
+func (x *Struct) RenameKeys(mapping map[string]string, recurse bool) *Struct {
+	if x == nil {
+		return nil
+	}
+	out := &Struct{Fields: make(map[string]*Value, len(x.Fields))}
+	for k, v := range x.Fields {
+		if recurse {
+			v = renameKeysInValue(v, mapping, recurse)
+		} else {
+			v = cloneValue(v)
+		}
+		if renamed, ok := mapping[k]; ok {
+			k = renamed
+		}
+		out.Fields[k] = v
+	}
+	return out
+}
+
+func renameKeysInValue(v *Value, mapping map[string]string, recurse bool) *Value {
+	switch k := v.GetKind().(type) {
+	case *Value_StructValue:
+		if k != nil {
+			return &Value{Kind: &Value_StructValue{StructValue: k.StructValue.RenameKeys(mapping, recurse)}}
+		}
+	case *Value_ListValue:
+		if k != nil {
+			values := make([]*Value, len(k.ListValue.GetValues()))
+			for i, ev := range k.ListValue.GetValues() {
+				values[i] = renameKeysInValue(ev, mapping, recurse)
+			}
+			return &Value{Kind: &Value_ListValue{ListValue: &ListValue{Values: values}}}
+		}
+	}
+	return cloneValue(v)
+}
@@ -0,0 +1,75 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructRenameKeysTopLevel(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"old_name": "x",
+		"kept":     1.0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := s.RenameKeys(map[string]string{"old_name": "new_name"}, false)
+
+	if _, ok := got.Fields["old_name"]; ok {
+		t.Error("old_name should have been renamed away")
+	}
+	if got.Fields["new_name"].AsInterface() != "x" {
+		t.Errorf("new_name = %v, want x", got.Fields["new_name"].AsInterface())
+	}
+	if got.Fields["kept"].AsInterface() != 1.0 {
+		t.Errorf("kept = %v, want 1.0", got.Fields["kept"].AsInterface())
+	}
+	if _, ok := s.Fields["old_name"]; !ok {
+		t.Error("RenameKeys should not mutate the receiver")
+	}
+}
+
+func TestStructRenameKeysRecurse(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"nested": map[string]interface{}{"old_name": "y"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapping := map[string]string{"old_name": "new_name"}
+
+	notRecursed := s.RenameKeys(mapping, false)
+	if _, ok := notRecursed.Fields["nested"].GetStructValue().Fields["old_name"]; !ok {
+		t.Error("without recurse, nested key should be untouched")
+	}
+
+	recursed := s.RenameKeys(mapping, true)
+	if _, ok := recursed.Fields["nested"].GetStructValue().Fields["old_name"]; ok {
+		t.Error("with recurse, nested old_name should have been renamed away")
+	}
+	if got := recursed.Fields["nested"].GetStructValue().Fields["new_name"].AsInterface(); got != "y" {
+		t.Errorf("nested.new_name = %v, want y", got)
+	}
+}
+
+func TestStructRenameKeysCollisionLastWins(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"a": 1.0,
+		"b": 2.0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := s.RenameKeys(map[string]string{"a": "c", "b": "c"}, false)
+	if len(got.Fields) != 1 {
+		t.Fatalf("got %d fields, want 1 after collision", len(got.Fields))
+	}
+	v := got.Fields["c"].AsInterface()
+	if v != 1.0 && v != 2.0 {
+		t.Errorf("c = %v, want 1.0 or 2.0", v)
+	}
+}
@@ -1,14 +1,329 @@
 package types_test
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"math"
+	"math/rand"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
 )
 
+func structForBenchmark() *types.Struct {
+	s, err := types.NewStruct(map[string]interface{}{
+		"a": "x",
+		"b": true,
+		"c": 3.0,
+		"d": []interface{}{1.0, 2.0, 3.0},
+		"e": map[string]interface{}{"f": "g"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func TestAsMapInto(t *testing.T) {
+	s := structForBenchmark()
+	want := s.AsMap()
+
+	got := make(map[string]interface{})
+	s.AsMapInto(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AsMapInto = %v, want %v", got, want)
+	}
+}
+
+func TestAsSliceInto(t *testing.T) {
+	lv := &types.ListValue{Values: []*types.Value{
+		{Kind: &types.Value_StringValue{StringValue: "x"}},
+		{Kind: &types.Value_NumberValue{NumberValue: 1}},
+	}}
+	want := lv.AsSlice()
+
+	got := lv.AsSliceInto(nil)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AsSliceInto(nil) = %v, want %v", got, want)
+	}
+
+	reused := make([]interface{}, 0, 8)
+	got = lv.AsSliceInto(reused)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AsSliceInto(reused) = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkAsMap(b *testing.B) {
+	s := structForBenchmark()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = s.AsMap()
+	}
+}
+
+func TestStructKeysLenValues(t *testing.T) {
+	var nilStruct *types.Struct
+	if got := nilStruct.Keys(); len(got) != 0 {
+		t.Errorf("nil Struct.Keys() = %v, want empty", got)
+	}
+	if got := nilStruct.Len(); got != 0 {
+		t.Errorf("nil Struct.Len() = %d, want 0", got)
+	}
+
+	s, err := types.NewStruct(map[string]interface{}{"c": 3.0, "a": 1.0, "b": 2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Keys(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	if got, want := s.Len(), 3; got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+	values := s.Values()
+	if len(values) != 3 || values[0].AsInterface() != 1.0 || values[2].AsInterface() != 3.0 {
+		t.Errorf("Values() = %v, want values for a, b, c in order", values)
+	}
+}
+
+func TestBytesValueEncodingRoundTrip(t *testing.T) {
+	data := []byte("hello world? this needs padding!!")
+
+	std := types.NewBytesValueEncoding(data, base64.StdEncoding)
+	got, err := std.AsBytes(base64.StdEncoding)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("StdEncoding round trip: got %q, %v, want %q, nil", got, err, data)
+	}
+
+	urlSafe := types.NewBytesValueEncoding(data, base64.RawURLEncoding)
+	got, err = urlSafe.AsBytes(base64.RawURLEncoding)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("RawURLEncoding round trip: got %q, %v, want %q, nil", got, err, data)
+	}
+
+	// With enc == nil, AsBytes should figure out RawURLEncoding too.
+	got, err = urlSafe.AsBytes(nil)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("AsBytes(nil) on RawURLEncoding value: got %q, %v, want %q, nil", got, err, data)
+	}
+}
+
+func TestListValueAppendLenAt(t *testing.T) {
+	lv := &types.ListValue{}
+	if got, want := lv.Len(), 0; got != want {
+		t.Errorf("empty Len() = %d, want %d", got, want)
+	}
+	if got := lv.At(0); got != nil {
+		t.Errorf("At(0) on empty = %v, want nil", got)
+	}
+
+	if err := lv.Append("x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := lv.Append(1.0); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := lv.Len(), 2; got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+	if got := lv.At(0).AsInterface(); got != "x" {
+		t.Errorf("At(0) = %v, want %q", got, "x")
+	}
+	if got := lv.At(1).AsInterface(); got != 1.0 {
+		t.Errorf("At(1) = %v, want %v", got, 1.0)
+	}
+	if got := lv.At(-1); got != nil {
+		t.Errorf("At(-1) = %v, want nil", got)
+	}
+	if got := lv.At(2); got != nil {
+		t.Errorf("At(2) (out of range) = %v, want nil", got)
+	}
+}
+
+func TestValueCompareSort(t *testing.T) {
+	mustValue := func(v interface{}) *types.Value {
+		pv, err := types.NewValue(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pv
+	}
+
+	values := []*types.Value{
+		mustValue(map[string]interface{}{"a": 1.0}),
+		mustValue([]interface{}{1.0}),
+		mustValue("x"),
+		mustValue(2.0),
+		mustValue(true),
+		mustValue(nil),
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return types.CompareValues(values[i], values[j]) < 0
+	})
+
+	want := []string{"null", "bool", "number", "string", "list", "struct"}
+	for i, v := range values {
+		var got string
+		switch v.GetKind().(type) {
+		case *types.Value_NullValue:
+			got = "null"
+		case *types.Value_BoolValue:
+			got = "bool"
+		case *types.Value_NumberValue:
+			got = "number"
+		case *types.Value_StringValue:
+			got = "string"
+		case *types.Value_ListValue:
+			got = "list"
+		case *types.Value_StructValue:
+			got = "struct"
+		}
+		if got != want[i] {
+			t.Errorf("position %d: got kind %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestCoerceStringNumbers(t *testing.T) {
+	data := []byte(`{"a":"42","b":"00501"}`)
+
+	var plain types.Struct
+	if err := json.Unmarshal(data, &plain); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if _, ok := plain.Fields["a"].GetKind().(*types.Value_StringValue); !ok {
+		t.Errorf("without CoerceStringNumbers, \"42\" = %v, want StringValue", plain.Fields["a"])
+	}
+
+	var coerced types.Struct
+	opts := types.UnmarshalOptions{CoerceStringNumbers: true}
+	if err := opts.Unmarshal(data, &coerced); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if n, ok := coerced.Fields["a"].GetKind().(*types.Value_NumberValue); !ok || n.NumberValue != 42 {
+		t.Errorf("with CoerceStringNumbers, \"42\" = %v, want NumberValue(42)", coerced.Fields["a"])
+	}
+	if _, ok := coerced.Fields["b"].GetKind().(*types.Value_NumberValue); !ok {
+		t.Errorf("with CoerceStringNumbers, \"00501\" = %v, want NumberValue", coerced.Fields["b"])
+	}
+}
+
+func TestNumberMarshalUnmarshalFixedPoint(t *testing.T) {
+	regression := []float64{0, 0.1, 1e-7, 123456789.123456789, -42, 1, 1e308, 5e-324, -0.0}
+	for _, f := range regression {
+		checkNumberRoundTrip(t, f)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		bits := r.Uint64()
+		f := math.Float64frombits(bits)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		checkNumberRoundTrip(t, f)
+	}
+}
+
+func checkNumberRoundTrip(t *testing.T, f float64) {
+	t.Helper()
+	v := types.NewNumberValue(f)
+	b, err := v.MarshalJSON()
+	if err != nil {
+		t.Errorf("MarshalJSON(%v): %v", f, err)
+		return
+	}
+	var got types.Value
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Errorf("Unmarshal(%s) (from %v): %v", b, f, err)
+		return
+	}
+	gf, ok := got.AsInterface().(float64)
+	if !ok || gf != f && !(math.IsNaN(gf) && math.IsNaN(f)) {
+		t.Errorf("round trip of %v via %s = %v, want %v", f, b, gf, f)
+	}
+}
+
+func TestStrictUnmarshalRejectsTrailingGarbage(t *testing.T) {
+	data := []byte(`{"a":1} garbage`)
+
+	var lenient types.Struct
+	if err := json.Unmarshal(data, &lenient); err == nil {
+		t.Fatalf("plain json.Unmarshal unexpectedly accepted trailing garbage")
+	}
+
+	var strict types.Struct
+	opts := types.UnmarshalOptions{Strict: true}
+	if err := opts.Unmarshal(data, &strict); err == nil {
+		t.Errorf("Strict Unmarshal(%s) succeeded, want error", data)
+	}
+
+	var ok types.Struct
+	if err := opts.Unmarshal([]byte(`{"a":1}`), &ok); err != nil {
+		t.Errorf("Strict Unmarshal of valid input failed: %v", err)
+	}
+}
+
+func TestDecoderReuse(t *testing.T) {
+	data := []byte(`{"a":1,"b":[1,2,3],"c":{"d":true}}`)
+	d := types.NewDecoder()
+
+	var first types.Value
+	if err := d.Decode(data, &first); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := first.AsInterface()
+
+	d.Reset()
+	var second types.Value
+	if err := d.Decode(data, &second); err != nil {
+		t.Fatalf("Decode after Reset: %v", err)
+	}
+	if got := second.AsInterface(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode after Reset = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkDecoderReuse(b *testing.B) {
+	data := []byte(`{"a":{"b":1,"c":[{"d":true},"f",2,null,3.5],"e":"hello world"},"g":[1,2,3,4,5]}`)
+	d := types.NewDecoder()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d.Reset()
+		var v types.Value
+		if err := d.Decode(data, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalNestedStruct(b *testing.B) {
+	data := []byte(`{"a":{"b":1,"c":[{"d":true},"f",2,null,3.5],"e":"hello world"},"g":[1,2,3,4,5]}`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := &types.Struct{}
+		if err := json.Unmarshal(data, s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAsMapInto(b *testing.B) {
+	s := structForBenchmark()
+	dst := make(map[string]interface{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.AsMapInto(dst)
+	}
+}
+
 var unmarshalingTests = []struct {
 	desc string
 	json string
@@ -101,3 +416,299 @@ func TestMarshaling(t *testing.T) {
 		}
 	}
 }
+
+func TestStructUnmarshalJSONRejectsNonObjectRoot(t *testing.T) {
+	tests := []struct {
+		json string
+		kind string
+	}{
+		{`[1,2,3]`, "array"},
+		{`"x"`, "string"},
+		{`5`, "number"},
+		{`true`, "boolean"},
+		{`null`, "null"},
+	}
+	for _, tt := range tests {
+		var s types.Struct
+		err := json.Unmarshal([]byte(tt.json), &s)
+		if err == nil {
+			t.Errorf("Unmarshal(%s): got nil error, want error", tt.json)
+			continue
+		}
+		want := "cannot unmarshal JSON " + tt.kind + " into Struct"
+		if err.Error() != want {
+			t.Errorf("Unmarshal(%s): got %q, want %q", tt.json, err.Error(), want)
+		}
+	}
+}
+
+func TestListValueUnmarshalJSONRejectsNonArrayRoot(t *testing.T) {
+	var lv types.ListValue
+	err := json.Unmarshal([]byte(`{"a":1}`), &lv)
+	if err == nil {
+		t.Fatal("got nil error, want error")
+	}
+	want := "cannot unmarshal JSON object into ListValue"
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestZeroCopyStrings(t *testing.T) {
+	data := []byte(`{"a":"hello","b":"with\\nescape"}`)
+	opts := types.UnmarshalOptions{ZeroCopyStrings: true}
+	var s types.Struct
+	if err := opts.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Fields["a"].GetStringValue(); got != "hello" {
+		t.Errorf("a = %q, want hello", got)
+	}
+	if got := s.Fields["b"].GetStringValue(); got != `with\nescape` {
+		t.Errorf("b = %q, want with\\nescape", got)
+	}
+}
+
+func BenchmarkUnmarshalManyShortStrings(b *testing.B) {
+	m := make(map[string]interface{}, 100)
+	for i := 0; i < 100; i++ {
+		m[string(rune('a'+i%26))+string(rune(i))] = "short"
+	}
+	s, err := types.NewStruct(m)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("copy", func(b *testing.B) {
+		opts := types.UnmarshalOptions{}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var out types.Struct
+			if err := opts.Unmarshal(data, &out); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("zerocopy", func(b *testing.B) {
+		opts := types.UnmarshalOptions{ZeroCopyStrings: true}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var out types.Struct
+			if err := opts.Unmarshal(data, &out); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestProtoSizeMatchesMarshal(t *testing.T) {
+	structs := []*types.Struct{
+		{},
+		mustNewStruct(t, map[string]interface{}{"a": 1.0}),
+		mustNewStruct(t, map[string]interface{}{
+			"name":   "alice",
+			"tags":   []interface{}{"x", "y", "z"},
+			"nested": map[string]interface{}{"b": true, "c": nil},
+		}),
+	}
+	for i, s := range structs {
+		data, err := proto.Marshal(s)
+		if err != nil {
+			t.Fatalf("structs[%d]: Marshal: %v", i, err)
+		}
+		if got, want := s.ProtoSize(), len(data); got != want {
+			t.Errorf("structs[%d]: ProtoSize() = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func mustNewStruct(t *testing.T, m map[string]interface{}) *types.Struct {
+	t.Helper()
+	s, err := types.NewStruct(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStructGetFold(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{"ID": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, ok := s.GetFold("id")
+	if !ok {
+		t.Fatal("GetFold(id): not found")
+	}
+	if got := v.AsInterface(); got != 1.0 {
+		t.Errorf("GetFold(id) = %v, want 1.0", got)
+	}
+
+	if _, ok := s.GetFold("missing"); ok {
+		t.Error("GetFold(missing): found, want not found")
+	}
+
+	// Fold-collision: both "id" and "Id" fold to the same key, so only one
+	// (unspecified which) survives map construction. Either present value
+	// must still be retrievable via GetFold.
+	collide, err := types.NewStruct(map[string]interface{}{"id": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	collide.Fields["Id"] = types.NewNumberValue(2.0)
+	v, ok = collide.GetFold("ID")
+	if !ok {
+		t.Fatal("GetFold(ID) on fold-colliding Struct: not found")
+	}
+	if got := v.AsInterface(); got != 1.0 && got != 2.0 {
+		t.Errorf("GetFold(ID) = %v, want 1.0 or 2.0", got)
+	}
+}
+
+func TestUnmarshalErrorIncludesPath(t *testing.T) {
+	// 1e400 is syntactically valid JSON but overflows float64, so the
+	// failure only surfaces once our own dispatch tries to parse it as a
+	// number deep inside the tree - a good test of path-threading, since
+	// the outer JSON syntax scan alone can't catch it.
+	data := []byte(`{"a":{"b":[1,2,{"c":true,"d":1e400}]}}`)
+	var s types.Struct
+	err := json.Unmarshal(data, &s)
+	if err == nil {
+		t.Fatal("got nil error, want error")
+	}
+	if want := "at a.b[2].d: "; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain path %q", err.Error(), want)
+	}
+}
+
+func TestNewValuePointerIndirection(t *testing.T) {
+	s := "hello"
+	v, err := types.NewValue(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.AsInterface(); got != "hello" {
+		t.Errorf("NewValue(&s) = %v, want hello", got)
+	}
+
+	var nilStr *string
+	v, err = types.NewValue(nilStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.GetKind().(*types.Value_NullValue); !ok {
+		t.Errorf("NewValue(nil *string) = %v, want NullValue", v)
+	}
+
+	m := map[string]interface{}{"a": 1.0}
+	v, err = types.NewValue(&m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.GetStructValue().Fields["a"].AsInterface(); got != 1.0 {
+		t.Errorf("NewValue(&map) field a = %v, want 1.0", got)
+	}
+}
+
+func TestMaxElementsRejectsOversizedArray(t *testing.T) {
+	data := []byte(`[1,2,3,4,5,6,7,8,9,10]`)
+	var lv types.ListValue
+	opts := types.UnmarshalOptions{MaxElements: 5}
+	err := opts.Unmarshal(data, &lv)
+	if err == nil {
+		t.Fatal("got nil error, want error")
+	}
+	if want := "exceeded max elements (5)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err.Error(), want)
+	}
+}
+
+func TestMaxElementsAllowsWithinLimit(t *testing.T) {
+	data := []byte(`[1,2,3]`)
+	var lv types.ListValue
+	opts := types.UnmarshalOptions{MaxElements: 3}
+	if err := opts.Unmarshal(data, &lv); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(lv.Values); got != 3 {
+		t.Errorf("len(Values) = %d, want 3", got)
+	}
+}
+
+func TestMaxFieldsRejectsOversizedObject(t *testing.T) {
+	data := []byte(`{"a":1,"b":2,"c":3}`)
+	var s types.Struct
+	opts := types.UnmarshalOptions{MaxFields: 2}
+	err := opts.Unmarshal(data, &s)
+	if err == nil {
+		t.Fatal("got nil error, want error")
+	}
+	if want := "exceeded max fields (2)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err.Error(), want)
+	}
+}
+
+func TestMaxFieldsAllowsWithinLimit(t *testing.T) {
+	data := []byte(`{"a":1,"b":2}`)
+	var s types.Struct
+	opts := types.UnmarshalOptions{MaxFields: 2}
+	if err := opts.Unmarshal(data, &s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(s.Fields); got != 2 {
+		t.Errorf("len(Fields) = %d, want 2", got)
+	}
+}
+
+func TestMaxStringLenRejectsOversizedStringValue(t *testing.T) {
+	data := []byte(`"hello world"`)
+	var v types.Value
+	opts := types.UnmarshalOptions{MaxStringLen: 5}
+	err := opts.Unmarshal(data, &v)
+	if err == nil {
+		t.Fatal("got nil error, want error")
+	}
+	if want := "exceeds max string length (5)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err.Error(), want)
+	}
+}
+
+func TestMaxStringLenRejectsOversizedKey(t *testing.T) {
+	data := []byte(`{"too_long_a_key":1}`)
+	var s types.Struct
+	opts := types.UnmarshalOptions{MaxStringLen: 5}
+	err := opts.Unmarshal(data, &s)
+	if err == nil {
+		t.Fatal("got nil error, want error")
+	}
+	if want := "exceeds max string length (5)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err.Error(), want)
+	}
+}
+
+func TestNewValueCoercesInterfaceMap(t *testing.T) {
+	m := map[interface{}]interface{}{
+		"a": "hello",
+		"b": 2.0,
+	}
+	v, err := types.NewValue(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := v.GetStructValue().GetFields()
+	if got := fields["a"].AsInterface(); got != "hello" {
+		t.Errorf("fields[a] = %v, want hello", got)
+	}
+	if got := fields["b"].AsInterface(); got != 2.0 {
+		t.Errorf("fields[b] = %v, want 2.0", got)
+	}
+
+	bad := map[interface{}]interface{}{1: "x"}
+	if _, err := types.NewValue(bad); err == nil {
+		t.Error("got nil error for non-string key, want error")
+	}
+}
@@ -0,0 +1,48 @@
+package types
+
+// This file exposes Struct accessors as text/template functions, so
+// config templates can safely navigate a dynamic Struct payload without
+// panicking on missing keys.
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// TemplateFuncs returns a template.FuncMap exposing structGet, structString,
+// and structHas, for use with (*text/template.Template).Funcs. Each
+// function takes a Struct and a dotted path (e.g. "a.b") and returns a
+// zero value rather than panicking when the path doesn't resolve.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"structGet":    templateStructGet,
+		"structString": templateStructString,
+		"structHas":    templateStructHas,
+	}
+}
+
+func templateStructGet(x *Struct, path string) interface{} {
+	v, ok := lookupPath(x, strings.Split(path, "."))
+	if !ok {
+		return nil
+	}
+	return v.AsInterface()
+}
+
+func templateStructString(x *Struct, path string) string {
+	v := templateStructGet(x, path)
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func templateStructHas(x *Struct, path string) bool {
+	_, ok := lookupPath(x, strings.Split(path, "."))
+	return ok
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	s, err := NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{"b": "hello"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := template.New("t").Funcs(TemplateFuncs()).Parse(
+		`{{ structString . "a.b" }}|{{ structHas . "a.b" }}|{{ structHas . "missing" }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "hello|true|false"; got != want {
+		t.Errorf("template output = %q, want %q", got, want)
+	}
+}
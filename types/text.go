@@ -0,0 +1,16 @@
+package types
+
+// CompactJSONText returns x's canonical JSON encoding. Unlike
+// proto.MarshalTextString, this is compact and has field order stable
+// across runs (encoding/json sorts map keys), which makes Structs play
+// nicely with loggers that want a single-line representation instead of
+// the verbose, unordered protobuf text format.
+//
+// This is deliberately not named MarshalText: proto.TextMarshaler
+// special-cases any field whose type implements encoding.TextMarshaler by
+// writing its output raw inside the text-format braces for that field, so
+// a Struct satisfying that interface with JSON output would corrupt
+// proto.MarshalText/UnmarshalText for every message embedding a Struct.
+func (x *Struct) CompactJSONText() ([]byte, error) {
+	return x.MarshalJSON()
+}
@@ -0,0 +1,41 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructCompactJSONTextStable(t *testing.T) {
+	s, err := types.NewStruct(map[string]interface{}{
+		"z": 1.0,
+		"a": 2.0,
+		"m": map[string]interface{}{"y": 1.0, "b": 2.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := s.CompactJSONText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		got, err := s.CompactJSONText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(first) {
+			t.Fatalf("run %d: CompactJSONText = %s, want %s (stable across runs)", i, got, first)
+		}
+	}
+
+	// CompactJSONText must not satisfy encoding.TextMarshaler: proto's text
+	// marshaler special-cases that interface and would otherwise dump this
+	// JSON raw inside the text-format braces for any message field of this
+	// type, corrupting proto.MarshalText/UnmarshalText round-tripping.
+	var i interface{} = s
+	if _, ok := i.(interface{ MarshalText() ([]byte, error) }); ok {
+		t.Fatal("*Struct must not implement encoding.TextMarshaler")
+	}
+}
@@ -133,3 +133,95 @@ func TimestampString(ts *Timestamp) string {
 func (x *Timestamp) AsTime() time.Time {
 	return time.Unix(int64(x.GetSeconds()), int64(x.GetNanos())).UTC()
 }
+
+// TimestampFromTime converts t to a Timestamp. Unlike TimestampProto, it
+// does not return an error for the common case of an in-range time;
+// instead, times before 0001-01-01 or on/after 10000-01-01 are clamped to
+// the nearest valid boundary.
+func TimestampFromTime(t time.Time) *Timestamp {
+	ts := &Timestamp{
+		Seconds: t.Unix(),
+		Nanos:   int32(t.Nanosecond()),
+	}
+	switch {
+	case ts.Seconds < minValidSeconds:
+		return &Timestamp{Seconds: minValidSeconds}
+	case ts.Seconds >= maxValidSeconds:
+		return &Timestamp{Seconds: maxValidSeconds - 1, Nanos: 999999999}
+	}
+	return ts
+}
+
+// ToTime converts ts to a time.Time, treating a nil Timestamp like the
+// empty Timestamp. It does not validate that ts falls within the valid
+// Timestamp range [0001-01-01, 10000-01-01); out-of-range values are
+// returned as whatever time.Unix computes for them.
+func (ts *Timestamp) ToTime() time.Time {
+	return time.Unix(ts.GetSeconds(), int64(ts.GetNanos())).UTC()
+}
+
+// TimestampPrecision selects how many fractional-second digits
+// TimestampMarshalOptions renders when marshaling a Timestamp to JSON.
+type TimestampPrecision int
+
+const (
+	// TimestampPrecisionNanosecond renders full nanosecond precision using
+	// RFC 3339. This is the default, and matches TimestampString.
+	TimestampPrecisionNanosecond TimestampPrecision = iota
+	// TimestampPrecisionMicrosecond truncates to microseconds.
+	TimestampPrecisionMicrosecond
+	// TimestampPrecisionMillisecond truncates to milliseconds.
+	TimestampPrecisionMillisecond
+	// TimestampPrecisionSecond truncates to whole seconds.
+	TimestampPrecisionSecond
+)
+
+// TimestampMarshalOptions configures how a Timestamp is rendered to and
+// parsed from its JSON (RFC 3339) representation. Some consumers choke on
+// sub-second digits, so Precision allows truncating them; the zero value
+// keeps the default full nanosecond precision.
+type TimestampMarshalOptions struct {
+	Precision TimestampPrecision
+}
+
+// Marshal renders ts as a JSON string according to o.Precision. It returns
+// an error if ts is not a valid Timestamp.
+func (o TimestampMarshalOptions) Marshal(ts *Timestamp) ([]byte, error) {
+	t, err := TimestampFromProto(ts)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(`"` + t.Format(o.layout()) + `"`), nil
+}
+
+// Unmarshal parses an RFC 3339 JSON string produced by Marshal (at any
+// precision) back into ts.
+func (o TimestampMarshalOptions) Unmarshal(data []byte, ts *Timestamp) error {
+	s := string(data)
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return fmt.Errorf("timestamp: %q is not a JSON string", data)
+	}
+	t, err := time.Parse(time.RFC3339Nano, s[1:len(s)-1])
+	if err != nil {
+		return err
+	}
+	tp, err := TimestampProto(t)
+	if err != nil {
+		return err
+	}
+	*ts = *tp
+	return nil
+}
+
+func (o TimestampMarshalOptions) layout() string {
+	switch o.Precision {
+	case TimestampPrecisionSecond:
+		return "2006-01-02T15:04:05Z07:00"
+	case TimestampPrecisionMillisecond:
+		return "2006-01-02T15:04:05.000Z07:00"
+	case TimestampPrecisionMicrosecond:
+		return "2006-01-02T15:04:05.000000Z07:00"
+	default:
+		return time.RFC3339Nano
+	}
+}
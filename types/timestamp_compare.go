@@ -0,0 +1,23 @@
+package types
+
+// This file adds convenience comparisons on top of google.protobuf.Timestamp
+// that avoid converting to time.Time.
+//
+// Note: a Compare(that interface{}) int method is already generated in
+// timestamp.pb.go by the gogoproto compare plugin, so it is not redeclared
+// here; it already implements exactly the seconds-then-nanos ordering this
+// would otherwise provide, just with an interface{} argument instead of a
+// *Timestamp one.
+
+// IsValid reports whether ts is a well-formed Timestamp: its Seconds field
+// falls within the representable range [0001-01-01, 10000-01-01), and its
+// Nanos field is in [0, 1e9). A nil ts is not valid.
+func (ts *Timestamp) IsValid() bool {
+	return ts != nil && validateTimestamp(ts) == nil
+}
+
+// Before reports whether ts represents an instant strictly before other.
+// As with Compare, a nil Timestamp sorts before any non-nil one.
+func (ts *Timestamp) Before(other *Timestamp) bool {
+	return ts.Compare(other) < 0
+}
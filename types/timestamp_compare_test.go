@@ -0,0 +1,40 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestTimestampCompareAndBefore(t *testing.T) {
+	earlier := &types.Timestamp{Seconds: 100, Nanos: 0}
+	later := &types.Timestamp{Seconds: 100, Nanos: 1}
+	same := &types.Timestamp{Seconds: 100, Nanos: 0}
+
+	if c := earlier.Compare(same); c != 0 {
+		t.Errorf("Compare(equal) = %d, want 0", c)
+	}
+	if !earlier.Before(later) {
+		t.Error("earlier.Before(later) = false, want true")
+	}
+	if later.Before(earlier) {
+		t.Error("later.Before(earlier) = true, want false")
+	}
+}
+
+func TestTimestampIsValid(t *testing.T) {
+	valid := &types.Timestamp{Seconds: 0, Nanos: 0}
+	if !valid.IsValid() {
+		t.Error("IsValid() = false for well-formed Timestamp, want true")
+	}
+
+	badNanos := &types.Timestamp{Seconds: 0, Nanos: 1e9}
+	if badNanos.IsValid() {
+		t.Error("IsValid() = true for out-of-range nanos, want false")
+	}
+
+	var nilTS *types.Timestamp
+	if nilTS.IsValid() {
+		t.Error("IsValid() = true for nil Timestamp, want false")
+	}
+}
@@ -151,6 +151,55 @@ func TestTimestampNow(t *testing.T) {
 	}
 }
 
+func TestTimestampMarshalOptionsPrecision(t *testing.T) {
+	ts := &Timestamp{Seconds: 1257894000, Nanos: 123456789}
+
+	tests := []struct {
+		precision TimestampPrecision
+		want      string
+	}{
+		{TimestampPrecisionNanosecond, `"2009-11-10T23:00:00.123456789Z"`},
+		{TimestampPrecisionMicrosecond, `"2009-11-10T23:00:00.123456Z"`},
+		{TimestampPrecisionMillisecond, `"2009-11-10T23:00:00.123Z"`},
+		{TimestampPrecisionSecond, `"2009-11-10T23:00:00Z"`},
+	}
+	for _, tt := range tests {
+		opts := TimestampMarshalOptions{Precision: tt.precision}
+		got, err := opts.Marshal(ts)
+		if err != nil {
+			t.Errorf("precision %v: Marshal: %v", tt.precision, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("precision %v: Marshal(%v) = %s, want %s", tt.precision, ts, got, tt.want)
+			continue
+		}
+
+		var roundTripped Timestamp
+		if err := opts.Unmarshal(got, &roundTripped); err != nil {
+			t.Errorf("precision %v: Unmarshal(%s): %v", tt.precision, got, err)
+			continue
+		}
+		if tt.precision == TimestampPrecisionNanosecond &&
+			(roundTripped.Seconds != ts.Seconds || roundTripped.Nanos != ts.Nanos) {
+			t.Errorf("precision %v: round trip = %v, want %v", tt.precision, roundTripped, ts)
+		}
+	}
+}
+
+func TestTimestampFromTimeAndToTime(t *testing.T) {
+	instant := time.Date(2009, 11, 10, 23, 0, 0, 123456789, time.UTC)
+	ts := TimestampFromTime(instant)
+	if got := ts.ToTime(); !got.Equal(instant) {
+		t.Errorf("ToTime(TimestampFromTime(%v)) = %v, want %v", instant, got, instant)
+	}
+
+	var zero Timestamp
+	if got, want := zero.ToTime(), time.Unix(0, 0).UTC(); !got.Equal(want) {
+		t.Errorf("zero Timestamp.ToTime() = %v, want %v", got, want)
+	}
+}
+
 func TestAsTime(t *testing.T) {
 	tn := time.Now()
 	ts, err := TimestampProto(tn)
@@ -0,0 +1,49 @@
+package types
+
+import "fmt"
+
+// AsStringSlice converts x to a []string, erroring with the offending
+// index if any element is not a StringValue. This is a checked alternative
+// to AsSlice for callers who already know a ListValue should be homogeneous.
+func (x *ListValue) AsStringSlice() ([]string, error) {
+	values := x.GetValues()
+	out := make([]string, len(values))
+	for i, v := range values {
+		sv, ok := v.GetKind().(*Value_StringValue)
+		if !ok {
+			return nil, fmt.Errorf("AsStringSlice: index %d is not a StringValue", i)
+		}
+		out[i] = sv.StringValue
+	}
+	return out, nil
+}
+
+// AsFloat64Slice converts x to a []float64, erroring with the offending
+// index if any element is not a NumberValue.
+func (x *ListValue) AsFloat64Slice() ([]float64, error) {
+	values := x.GetValues()
+	out := make([]float64, len(values))
+	for i, v := range values {
+		nv, ok := v.GetKind().(*Value_NumberValue)
+		if !ok {
+			return nil, fmt.Errorf("AsFloat64Slice: index %d is not a NumberValue", i)
+		}
+		out[i] = nv.NumberValue
+	}
+	return out, nil
+}
+
+// AsBoolSlice converts x to a []bool, erroring with the offending index if
+// any element is not a BoolValue.
+func (x *ListValue) AsBoolSlice() ([]bool, error) {
+	values := x.GetValues()
+	out := make([]bool, len(values))
+	for i, v := range values {
+		bv, ok := v.GetKind().(*Value_BoolValue)
+		if !ok {
+			return nil, fmt.Errorf("AsBoolSlice: index %d is not a BoolValue", i)
+		}
+		out[i] = bv.BoolValue
+	}
+	return out, nil
+}
@@ -0,0 +1,64 @@
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestListValueAsStringSlice(t *testing.T) {
+	lv, err := types.NewList([]interface{}{"a", "b", "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := lv.AsStringSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a", "b", "c"}
+	if len(got) != len(want) {
+		t.Fatalf("AsStringSlice() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("AsStringSlice()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestListValueAsStringSliceMixedTypeError(t *testing.T) {
+	lv, err := types.NewList([]interface{}{"a", 1.0, "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lv.AsStringSlice(); err == nil {
+		t.Fatal("got nil error for mixed-type list, want error")
+	} else if !strings.Contains(err.Error(), "index 1") {
+		t.Errorf("error %q does not mention offending index", err)
+	}
+}
+
+func TestListValueAsFloat64SliceAndAsBoolSlice(t *testing.T) {
+	nums, err := types.NewList([]interface{}{1.0, 2.0, 3.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	floats, err := nums.AsFloat64Slice()
+	if err != nil || len(floats) != 3 || floats[1] != 2.0 {
+		t.Errorf("AsFloat64Slice() = %v, %v, want [1 2 3], nil", floats, err)
+	}
+
+	bools, err := types.NewList([]interface{}{true, false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := bools.AsBoolSlice()
+	if err != nil || len(got) != 2 || got[0] != true || got[1] != false {
+		t.Errorf("AsBoolSlice() = %v, %v, want [true false], nil", got, err)
+	}
+
+	if _, err := nums.AsBoolSlice(); err == nil {
+		t.Error("got nil error for numbers via AsBoolSlice, want error")
+	}
+}
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// UnmarshalToMap parses b, which must hold a JSON object, directly into a
+// map[string]interface{}, using the same token rules (string unescaping,
+// float64 number parsing) as Struct's own unmarshaling, but without
+// allocating any intermediate Values along the way. This is for callers
+// who only ever wanted AsMap's result and were paying for the Struct in
+// between for nothing.
+func UnmarshalToMap(b []byte) (map[string]interface{}, error) {
+	if kind := jsonRootKind(b); kind != "" && kind != "object" {
+		return nil, fmt.Errorf("cannot unmarshal JSON %s into map", kind)
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("bad StructValue: %v", err)
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, raw := range m {
+		v, err := unmarshalToInterface(raw)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+// unmarshalToInterface decodes a single JSON value the same way
+// Value.unmarshalWith does, except it produces a plain interface{} instead
+// of a *Value.
+func unmarshalToInterface(raw json.RawMessage) (interface{}, error) {
+	v := bytes.TrimSpace(raw)
+	if len(v) == 0 {
+		return nil, fmt.Errorf("unrecognized type for Value %q", raw)
+	}
+	switch v[0] {
+	case 'n':
+		return nil, nil
+	case 't', 'f':
+		b, err := strconv.ParseBool(string(v))
+		if err != nil {
+			return nil, fmt.Errorf("unrecognized type for Value %q", v)
+		}
+		return b, nil
+	case '"':
+		s, err := unquote(string(v))
+		if err != nil {
+			return nil, fmt.Errorf("unrecognized type for Value %q", v)
+		}
+		return s, nil
+	case '[':
+		var s []json.RawMessage
+		if err := json.Unmarshal(v, &s); err != nil {
+			return nil, fmt.Errorf("bad ListValue: %v", err)
+		}
+		out := make([]interface{}, len(s))
+		for i, sv := range s {
+			ev, err := unmarshalToInterface(sv)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = ev
+		}
+		return out, nil
+	case '{':
+		return UnmarshalToMap(v)
+	default:
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("unrecognized type for Value %q", v)
+		}
+		return f, nil
+	}
+}
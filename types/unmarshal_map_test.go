@@ -0,0 +1,33 @@
+package types_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestUnmarshalToMapMatchesStructAsMap(t *testing.T) {
+	data := []byte(`{"a":"x","b":3,"c":true,"d":null,"e":[1,"y",false],"f":{"g":1}}`)
+
+	got, err := types.UnmarshalToMap(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s types.Struct
+	if err := s.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	want := s.AsMap()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalToMap() = %#v, want %#v", got, want)
+	}
+}
+
+func TestUnmarshalToMapRejectsNonObjectRoot(t *testing.T) {
+	if _, err := types.UnmarshalToMap([]byte(`[1,2,3]`)); err == nil {
+		t.Error("expected error for array root, got nil")
+	}
+}
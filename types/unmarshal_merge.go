@@ -0,0 +1,18 @@
+package types
+
+// UnmarshalMerge decodes b and merges the result onto x's existing fields
+// in place, recursing into nested Structs present in both, the same way
+// MergeStructWith merges one Struct into another. Unlike UnmarshalJSON,
+// which always replaces x.Fields wholesale, this lets callers layer JSON
+// fragments onto a base Struct one decode at a time. A field present in
+// both x and the decoded fragment is overwritten by the fragment's value,
+// except where both are Structs, which are merged recursively; lists are
+// replaced wholesale, same as MergeStructWith's default ListReplace.
+func (x *Struct) UnmarshalMerge(b []byte) error {
+	var patch Struct
+	if err := patch.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	MergeStructWith(x, &patch, MergeOptions{})
+	return nil
+}
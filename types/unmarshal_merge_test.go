@@ -0,0 +1,30 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+func TestStructUnmarshalMergeUnionsFragments(t *testing.T) {
+	var s types.Struct
+	if err := s.UnmarshalMerge([]byte(`{"a":1,"nested":{"x":1}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UnmarshalMerge([]byte(`{"b":2,"nested":{"y":2}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := types.NewStruct(map[string]interface{}{
+		"a":      1.0,
+		"b":      2.0,
+		"nested": map[string]interface{}{"x": 1.0, "y": 2.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(&s, want) {
+		t.Errorf("UnmarshalMerge() = %v, want %v", &s, want)
+	}
+}
@@ -0,0 +1,68 @@
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// Struct keys are always decoded through json.Unmarshal into a Go map,
+// never through the ZeroCopyStrings fast path, so encoding/json's ordinary
+// unescaping already sanitizes any invalid UTF-8 bytes in a key before it
+// reaches validateUTF8; these tests document that a key decodes the same,
+// valid way regardless of mode. String values have no such guarantee (see
+// the ZeroCopyStrings tests below), which is the actual gap this closes.
+func TestDecodeInvalidUTF8KeyAlreadySanitizedStrictMode(t *testing.T) {
+	data := []byte("{\"a\xffb\":1}")
+	var s types.Struct
+	if err := (types.UnmarshalOptions{}).Unmarshal(data, &s); err != nil {
+		t.Fatalf("Unmarshal() error = %v, want nil", err)
+	}
+	if len(s.Fields) != 1 {
+		t.Fatalf("len(Fields) = %d, want 1", len(s.Fields))
+	}
+	for k := range s.Fields {
+		if !strings.Contains(k, "�") {
+			t.Errorf("key = %q, want replacement character in place of invalid byte", k)
+		}
+	}
+}
+
+func TestDecodeInvalidUTF8KeyAlreadySanitizedReplaceMode(t *testing.T) {
+	data := []byte("{\"a\xffb\":1}")
+	var s types.Struct
+	opts := types.UnmarshalOptions{ReplaceInvalidUTF8: true}
+	if err := opts.Unmarshal(data, &s); err != nil {
+		t.Fatalf("Unmarshal() error = %v, want nil", err)
+	}
+	if len(s.Fields) != 1 {
+		t.Fatalf("len(Fields) = %d, want 1", len(s.Fields))
+	}
+	for k := range s.Fields {
+		if !strings.Contains(k, "�") {
+			t.Errorf("key = %q, want replacement character in place of invalid byte", k)
+		}
+	}
+}
+
+func TestDecodeInvalidUTF8StringValueStrictRejects(t *testing.T) {
+	data := []byte("\"a\xffb\"")
+	var v types.Value
+	err := types.UnmarshalOptions{ZeroCopyStrings: true}.Unmarshal(data, &v)
+	if err == nil {
+		t.Fatal("Unmarshal() error = nil, want error for invalid UTF-8 string")
+	}
+}
+
+func TestDecodeInvalidUTF8StringValueReplaceModeSubstitutes(t *testing.T) {
+	data := []byte("\"a\xffb\"")
+	var v types.Value
+	opts := types.UnmarshalOptions{ZeroCopyStrings: true, ReplaceInvalidUTF8: true}
+	if err := opts.Unmarshal(data, &v); err != nil {
+		t.Fatalf("Unmarshal() error = %v, want nil", err)
+	}
+	if got := v.GetStringValue(); !strings.Contains(got, "�") {
+		t.Errorf("StringValue = %q, want replacement character in place of invalid byte", got)
+	}
+}
@@ -0,0 +1,57 @@
+package types
+
+// ValueKind identifies which oneof case a Value holds, as a plain
+// comparable type suitable for switch statements, instead of matching on
+// the concrete *Value_XxxValue oneof wrapper structs.
+type ValueKind int
+
+const (
+	// KindInvalid means x is nil or has no Kind set.
+	KindInvalid ValueKind = iota
+	KindNull
+	KindBool
+	KindNumber
+	KindString
+	KindStruct
+	KindList
+)
+
+func (k ValueKind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "bool"
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	case KindStruct:
+		return "struct"
+	case KindList:
+		return "list"
+	default:
+		return "invalid"
+	}
+}
+
+// ValueKind reports which oneof case x holds. A nil x, or one with no
+// Kind set, reports KindInvalid.
+func (x *Value) ValueKind() ValueKind {
+	switch x.GetKind().(type) {
+	case *Value_NullValue:
+		return KindNull
+	case *Value_BoolValue:
+		return KindBool
+	case *Value_NumberValue:
+		return KindNumber
+	case *Value_StringValue:
+		return KindString
+	case *Value_StructValue:
+		return KindStruct
+	case *Value_ListValue:
+		return KindList
+	default:
+		return KindInvalid
+	}
+}
@@ -0,0 +1,38 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestValueKindMatchesConstructor(t *testing.T) {
+	s, err := types.NewStruct(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := types.NewList(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		v    *types.Value
+		want types.ValueKind
+	}{
+		{"null", types.NewNullValue(), types.KindNull},
+		{"bool", types.NewBoolValue(true), types.KindBool},
+		{"number", types.NewNumberValue(1.5), types.KindNumber},
+		{"string", types.NewStringValue("x"), types.KindString},
+		{"struct", types.NewStructValue(s), types.KindStruct},
+		{"list", types.NewListValue(l), types.KindList},
+		{"nil", nil, types.KindInvalid},
+		{"empty", &types.Value{}, types.KindInvalid},
+	}
+	for _, c := range cases {
+		if got := c.v.ValueKind(); got != c.want {
+			t.Errorf("%s: ValueKind() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
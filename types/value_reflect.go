@@ -0,0 +1,119 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NewValueReflect constructs a Value from an arbitrary Go value using
+// reflection, recursing into struct fields (exported fields only, using
+// their name as the Struct key), slices/arrays (as ListValue), maps with
+// string keys (as StructValue), and pointers (dereferenced, with a nil
+// pointer stored as NullValue), in addition to everything NewValue already
+// supports.
+//
+// Unlike NewValue, NewValueReflect follows pointers found inside structs,
+// slices, and maps, so a cyclic object graph would otherwise recurse
+// forever; it guards against this by tracking the pointers already visited
+// on the current path and returning an error if one is seen again.
+func NewValueReflect(v interface{}) (*Value, error) {
+	return newValueReflect(v, map[uintptr]bool{})
+}
+
+func newValueReflect(v interface{}, visited map[uintptr]bool) (*Value, error) {
+	if direct, err := NewValue(v); err == nil {
+		return direct, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	return newValueReflectValue(rv, visited)
+}
+
+func newValueReflectValue(rv reflect.Value, visited map[uintptr]bool) (*Value, error) {
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return NewNullValue(), nil
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return NewNullValue(), nil
+		}
+		ptr := rv.Pointer()
+		if visited[ptr] {
+			return nil, fmt.Errorf("types: NewValueReflect: cycle detected")
+		}
+		visited[ptr] = true
+		defer delete(visited, ptr)
+		return newValueReflectValue(rv.Elem(), visited)
+	case reflect.Interface:
+		if rv.IsNil() {
+			return NewNullValue(), nil
+		}
+		return newValueReflectValue(rv.Elem(), visited)
+	case reflect.Struct:
+		t := rv.Type()
+		fields := make(map[string]*Value, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.PkgPath != "" {
+				continue // unexported
+			}
+			fv, err := newValueReflectValue(rv.Field(i), visited)
+			if err != nil {
+				return nil, err
+			}
+			fields[sf.Name] = fv
+		}
+		return NewStructValue(&Struct{Fields: fields}), nil
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return newValueReflect(rv.Bytes(), visited)
+		}
+		if rv.Kind() == reflect.Slice {
+			if rv.IsNil() {
+				return NewNullValue(), nil
+			}
+			ptr := rv.Pointer()
+			if visited[ptr] {
+				return nil, fmt.Errorf("types: NewValueReflect: cycle detected")
+			}
+			visited[ptr] = true
+			defer delete(visited, ptr)
+		}
+		values := make([]*Value, rv.Len())
+		for i := range values {
+			ev, err := newValueReflectValue(rv.Index(i), visited)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = ev
+		}
+		return NewListValue(&ListValue{Values: values}), nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("types: NewValueReflect: unsupported map key type %v", rv.Type().Key())
+		}
+		if rv.IsNil() {
+			return NewNullValue(), nil
+		}
+		ptr := rv.Pointer()
+		if visited[ptr] {
+			return nil, fmt.Errorf("types: NewValueReflect: cycle detected")
+		}
+		visited[ptr] = true
+		defer delete(visited, ptr)
+		fields := make(map[string]*Value, rv.Len())
+		for _, k := range rv.MapKeys() {
+			fv, err := newValueReflectValue(rv.MapIndex(k), visited)
+			if err != nil {
+				return nil, err
+			}
+			fields[k.String()] = fv
+		}
+		return NewStructValue(&Struct{Fields: fields}), nil
+	default:
+		if !rv.IsValid() {
+			return NewNullValue(), nil
+		}
+		return NewValue(rv.Interface())
+	}
+}
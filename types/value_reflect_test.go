@@ -0,0 +1,45 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestNewValueReflectStructAndSlice(t *testing.T) {
+	type inner struct {
+		Name string
+	}
+	type outer struct {
+		Inner inner
+		Tags  []string
+		unexp int
+	}
+
+	v, err := types.NewValueReflect(outer{Inner: inner{Name: "x"}, Tags: []string{"a", "b"}, unexp: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Inner":{"Name":"x"},"Tags":["a","b"]}`; string(b) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", b, want)
+	}
+}
+
+type cyclicNode struct {
+	Next *cyclicNode
+}
+
+func TestNewValueReflectCycleDetected(t *testing.T) {
+	n := &cyclicNode{}
+	n.Next = n
+
+	_, err := types.NewValueReflect(n)
+	if err == nil {
+		t.Fatal("NewValueReflect() of a self-referential struct = nil error, want error")
+	}
+}
@@ -0,0 +1,72 @@
+package types
+
+// This file implements generic traversal over a Value tree: Walk visits
+// every node, and MapLeaves rebuilds the tree with leaves transformed.
+// Other tree-wide operations (redaction, diffing, statistics) can be
+// expressed in terms of either.
+
+import "fmt"
+
+// Walk invokes f for every node in x's tree, depth-first, visiting a node
+// before its children (pre-order). The root is visited with path "".
+// Struct fields are visited in sorted key order as "parent.key" (or just
+// "key" at the root), and list elements as "parent[i]". Walk stops and
+// returns the first error returned by f. Walk is nil-safe.
+func (x *Value) Walk(f func(path string, v *Value) error) error {
+	return walkValue(x, "", f)
+}
+
+func walkValue(v *Value, path string, f func(string, *Value) error) error {
+	if err := f(path, v); err != nil {
+		return err
+	}
+	switch k := v.GetKind().(type) {
+	case *Value_StructValue:
+		for _, key := range k.StructValue.Keys() {
+			if err := walkValue(k.StructValue.Fields[key], joinPath(path, key), f); err != nil {
+				return err
+			}
+		}
+	case *Value_ListValue:
+		for i, child := range k.ListValue.GetValues() {
+			if err := walkValue(child, fmt.Sprintf("%s[%d]", path, i), f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// MapLeaves returns a deep copy of x with every leaf (number, string,
+// bool, or null) replaced by the result of calling f with its path and
+// current value. Struct and list nodes are copied as-is, recursing into
+// their children; x itself is left untouched.
+func (x *Value) MapLeaves(f func(path string, v *Value) *Value) *Value {
+	return mapLeaves(x, "", f)
+}
+
+func mapLeaves(v *Value, path string, f func(string, *Value) *Value) *Value {
+	switch k := v.GetKind().(type) {
+	case *Value_StructValue:
+		fields := make(map[string]*Value, len(k.StructValue.GetFields()))
+		for key, child := range k.StructValue.GetFields() {
+			fields[key] = mapLeaves(child, joinPath(path, key), f)
+		}
+		return NewStructValue(&Struct{Fields: fields})
+	case *Value_ListValue:
+		values := make([]*Value, len(k.ListValue.GetValues()))
+		for i, child := range k.ListValue.GetValues() {
+			values[i] = mapLeaves(child, fmt.Sprintf("%s[%d]", path, i), f)
+		}
+		return NewListValue(&ListValue{Values: values})
+	default:
+		return f(path, v)
+	}
+}
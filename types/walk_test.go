@@ -0,0 +1,60 @@
+package types
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValueWalk(t *testing.T) {
+	s, err := NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{"b": 1.0},
+		"c": []interface{}{"x", "y"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewStructValue(s)
+
+	var paths []string
+	if err := v.Walk(func(path string, _ *Value) error {
+		paths = append(paths, path)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"", "a", "a.b", "c", "c[0]", "c[1]"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("visited paths = %v, want %v", paths, want)
+	}
+}
+
+func TestValueMapLeaves(t *testing.T) {
+	s, err := NewStruct(map[string]interface{}{
+		"a": map[string]interface{}{"b": "hello"},
+		"c": []interface{}{"world"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := NewStructValue(s)
+
+	upper := orig.MapLeaves(func(_ string, v *Value) *Value {
+		if sv, ok := v.GetKind().(*Value_StringValue); ok {
+			return NewStringValue(strings.ToUpper(sv.StringValue))
+		}
+		return v
+	})
+
+	if got := upper.GetStructValue().GetFields()["a"].GetStructValue().GetFields()["b"].GetStringValue(); got != "HELLO" {
+		t.Errorf("nested leaf = %q, want HELLO", got)
+	}
+	if got := upper.GetStructValue().GetFields()["c"].GetListValue().GetValues()[0].GetStringValue(); got != "WORLD" {
+		t.Errorf("list leaf = %q, want WORLD", got)
+	}
+	// Original is untouched.
+	if got := orig.GetStructValue().GetFields()["a"].GetStructValue().GetFields()["b"].GetStringValue(); got != "hello" {
+		t.Errorf("original mutated: got %q, want hello", got)
+	}
+}
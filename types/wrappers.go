@@ -0,0 +1,95 @@
+package types
+
+// This file provides convenience constructors and nil-safe extractors for
+// the wrapper well-known types, mirroring the ergonomics of upstream
+// protobuf's wrapperspb package.
+
+// Bool stores v in a new BoolValue.
+func Bool(v bool) *BoolValue {
+	return &BoolValue{Value: v}
+}
+
+// Get returns w.Value, or the zero value if w is nil.
+func (w *BoolValue) Get() bool {
+	return w.GetValue()
+}
+
+// Int32 stores v in a new Int32Value.
+func Int32(v int32) *Int32Value {
+	return &Int32Value{Value: v}
+}
+
+// Get returns w.Value, or the zero value if w is nil.
+func (w *Int32Value) Get() int32 {
+	return w.GetValue()
+}
+
+// Int64 stores v in a new Int64Value.
+func Int64(v int64) *Int64Value {
+	return &Int64Value{Value: v}
+}
+
+// Get returns w.Value, or the zero value if w is nil.
+func (w *Int64Value) Get() int64 {
+	return w.GetValue()
+}
+
+// UInt32 stores v in a new UInt32Value.
+func UInt32(v uint32) *UInt32Value {
+	return &UInt32Value{Value: v}
+}
+
+// Get returns w.Value, or the zero value if w is nil.
+func (w *UInt32Value) Get() uint32 {
+	return w.GetValue()
+}
+
+// UInt64 stores v in a new UInt64Value.
+func UInt64(v uint64) *UInt64Value {
+	return &UInt64Value{Value: v}
+}
+
+// Get returns w.Value, or the zero value if w is nil.
+func (w *UInt64Value) Get() uint64 {
+	return w.GetValue()
+}
+
+// Float stores v in a new FloatValue.
+func Float(v float32) *FloatValue {
+	return &FloatValue{Value: v}
+}
+
+// Get returns w.Value, or the zero value if w is nil.
+func (w *FloatValue) Get() float32 {
+	return w.GetValue()
+}
+
+// Double stores v in a new DoubleValue.
+func Double(v float64) *DoubleValue {
+	return &DoubleValue{Value: v}
+}
+
+// Get returns w.Value, or the zero value if w is nil.
+func (w *DoubleValue) Get() float64 {
+	return w.GetValue()
+}
+
+// String stores v in a new StringValue.
+func String(v string) *StringValue {
+	return &StringValue{Value: v}
+}
+
+// Get returns w.Value, or the zero value if w is nil.
+func (w *StringValue) Get() string {
+	return w.GetValue()
+}
+
+// Bytes stores v in a new BytesValue.
+func Bytes(v []byte) *BytesValue {
+	return &BytesValue{Value: v}
+}
+
+// Get returns w.Value, or nil if w is nil.
+func (w *BytesValue) Get() []byte {
+	return w.GetValue()
+}
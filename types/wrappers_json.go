@@ -0,0 +1,160 @@
+package types
+
+// This file implements the canonical proto3 JSON mapping for the wrapper
+// well-known types: "Wrappers use the same representation in JSON as the
+// wrapped primitive type, except that null is allowed and preserved during
+// data conversion and transfer." Without these, encoding/json's default
+// struct reflection would instead marshal e.g. an Int64Value as
+// {"value":123}, which is not a valid wrapper JSON representation.
+//
+// Per the proto3 JSON mapping, int64 and uint64 (and therefore Int64Value
+// and UInt64Value) are rendered as JSON strings, to survive round-tripping
+// through JSON numbers, which are not guaranteed 64-bit precision; every
+// other wrapper type uses its natural JSON representation.
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// MarshalJSON renders w as its bare wrapped value, per the wrapper JSON
+// mapping, instead of {"value":...}.
+func (w *DoubleValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.GetValue())
+}
+
+// UnmarshalJSON parses a bare JSON number into w.
+func (w *DoubleValue) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &w.Value)
+}
+
+// MarshalJSON renders w as its bare wrapped value, per the wrapper JSON
+// mapping, instead of {"value":...}.
+func (w *FloatValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.GetValue())
+}
+
+// UnmarshalJSON parses a bare JSON number into w.
+func (w *FloatValue) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &w.Value)
+}
+
+// MarshalJSON renders w as a quoted decimal string, per the proto3 JSON
+// mapping for int64 (chosen because JSON numbers aren't guaranteed to hold
+// 64 bits of integer precision).
+func (w *Int64Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(w.GetValue(), 10))
+}
+
+// UnmarshalJSON parses a quoted decimal string, or (leniently) a bare JSON
+// number, into w.
+func (w *Int64Value) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalInt64JSON(b)
+	if err != nil {
+		return err
+	}
+	w.Value = v
+	return nil
+}
+
+// MarshalJSON renders w as a quoted decimal string, per the proto3 JSON
+// mapping for uint64 (chosen because JSON numbers aren't guaranteed to hold
+// 64 bits of integer precision).
+func (w *UInt64Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(w.GetValue(), 10))
+}
+
+// UnmarshalJSON parses a quoted decimal string, or (leniently) a bare JSON
+// number, into w.
+func (w *UInt64Value) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalUint64JSON(b)
+	if err != nil {
+		return err
+	}
+	w.Value = v
+	return nil
+}
+
+// MarshalJSON renders w as its bare wrapped value, per the wrapper JSON
+// mapping, instead of {"value":...}.
+func (w *Int32Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.GetValue())
+}
+
+// UnmarshalJSON parses a bare JSON number into w.
+func (w *Int32Value) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &w.Value)
+}
+
+// MarshalJSON renders w as its bare wrapped value, per the wrapper JSON
+// mapping, instead of {"value":...}.
+func (w *UInt32Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.GetValue())
+}
+
+// UnmarshalJSON parses a bare JSON number into w.
+func (w *UInt32Value) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &w.Value)
+}
+
+// MarshalJSON renders w as its bare wrapped value, per the wrapper JSON
+// mapping, instead of {"value":...}.
+func (w *BoolValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.GetValue())
+}
+
+// UnmarshalJSON parses a bare JSON boolean into w.
+func (w *BoolValue) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &w.Value)
+}
+
+// MarshalJSON renders w as its bare wrapped value, per the wrapper JSON
+// mapping, instead of {"value":...}.
+func (w *StringValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.GetValue())
+}
+
+// UnmarshalJSON parses a bare JSON string into w.
+func (w *StringValue) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &w.Value)
+}
+
+// MarshalJSON renders w as its bare wrapped value, base64-encoded per the
+// proto3 JSON mapping for bytes (the same mapping []byte gets from
+// encoding/json).
+func (w *BytesValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.GetValue())
+}
+
+// UnmarshalJSON parses a base64-encoded JSON string into w.
+func (w *BytesValue) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &w.Value)
+}
+
+// unmarshalInt64JSON accepts either a quoted decimal string (the canonical
+// wrapper JSON mapping) or a bare JSON number (for leniency with
+// producers that don't quote 64-bit integers).
+func unmarshalInt64JSON(b []byte) (int64, error) {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		return strconv.ParseInt(s, 10, 64)
+	}
+	var n int64
+	if err := json.Unmarshal(b, &n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// unmarshalUint64JSON is the uint64 counterpart of unmarshalInt64JSON.
+func unmarshalUint64JSON(b []byte) (uint64, error) {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		return strconv.ParseUint(s, 10, 64)
+	}
+	var n uint64
+	if err := json.Unmarshal(b, &n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
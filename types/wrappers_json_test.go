@@ -0,0 +1,86 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestWrapperJSONExactOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		v    json.Marshaler
+		want string
+	}{
+		{"DoubleValue", types.Double(1.5), "1.5"},
+		{"FloatValue", types.Float(1.5), "1.5"},
+		{"Int64Value", types.Int64(-123), `"-123"`},
+		{"UInt64Value", types.UInt64(123), `"123"`},
+		{"Int32Value", types.Int32(-7), "-7"},
+		{"UInt32Value", types.UInt32(7), "7"},
+		{"BoolValue", types.Bool(true), "true"},
+		{"StringValue", types.String("x"), `"x"`},
+		{"BytesValue", types.Bytes([]byte("x")), `"eA=="`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.v.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapperJSONRoundTrip(t *testing.T) {
+	i64 := types.Int64(-9223372036854775808)
+	b, err := i64.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got types.Int64Value
+	if err := got.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if got.Value != i64.Value {
+		t.Errorf("round trip = %d, want %d", got.Value, i64.Value)
+	}
+
+	u64 := types.UInt64(18446744073709551615)
+	b, err = u64.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotU types.UInt64Value
+	if err := gotU.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if gotU.Value != u64.Value {
+		t.Errorf("round trip = %d, want %d", gotU.Value, u64.Value)
+	}
+}
+
+func TestWrapperJSONUnmarshalLenientBareNumber(t *testing.T) {
+	var got types.Int64Value
+	if err := got.UnmarshalJSON([]byte("42")); err != nil {
+		t.Fatal(err)
+	}
+	if got.Value != 42 {
+		t.Errorf("Value = %d, want 42", got.Value)
+	}
+}
+
+func TestWrapperJSONNilMarshalsNull(t *testing.T) {
+	var i64 *types.Int64Value
+	b, err := json.Marshal(i64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "null" {
+		t.Errorf("Marshal(nil) = %s, want null", b)
+	}
+}
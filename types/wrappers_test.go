@@ -0,0 +1,74 @@
+package types
+
+import "testing"
+
+func TestWrapperConstructors(t *testing.T) {
+	if got, want := Bool(true).GetValue(), true; got != want {
+		t.Errorf("Bool(%v).GetValue() = %v, want %v", want, got, want)
+	}
+	if got, want := Int32(7).GetValue(), int32(7); got != want {
+		t.Errorf("Int32(%v).GetValue() = %v, want %v", want, got, want)
+	}
+	if got, want := Int64(7).GetValue(), int64(7); got != want {
+		t.Errorf("Int64(%v).GetValue() = %v, want %v", want, got, want)
+	}
+	if got, want := UInt32(7).GetValue(), uint32(7); got != want {
+		t.Errorf("UInt32(%v).GetValue() = %v, want %v", want, got, want)
+	}
+	if got, want := UInt64(7).GetValue(), uint64(7); got != want {
+		t.Errorf("UInt64(%v).GetValue() = %v, want %v", want, got, want)
+	}
+	if got, want := Float(1.5).GetValue(), float32(1.5); got != want {
+		t.Errorf("Float(%v).GetValue() = %v, want %v", want, got, want)
+	}
+	if got, want := Double(1.5).GetValue(), 1.5; got != want {
+		t.Errorf("Double(%v).GetValue() = %v, want %v", want, got, want)
+	}
+	if got, want := String("x").GetValue(), "x"; got != want {
+		t.Errorf("String(%v).GetValue() = %v, want %v", want, got, want)
+	}
+	if got, want := string(Bytes([]byte("x")).GetValue()), "x"; got != want {
+		t.Errorf("Bytes(%v).GetValue() = %v, want %v", want, got, want)
+	}
+}
+
+func TestWrapperGetNilSafety(t *testing.T) {
+	var (
+		b  *BoolValue
+		i3 *Int32Value
+		i6 *Int64Value
+		u3 *UInt32Value
+		u6 *UInt64Value
+		f  *FloatValue
+		d  *DoubleValue
+		s  *StringValue
+		by *BytesValue
+	)
+	if got := b.Get(); got != false {
+		t.Errorf("nil BoolValue.Get() = %v, want false", got)
+	}
+	if got := i3.Get(); got != 0 {
+		t.Errorf("nil Int32Value.Get() = %v, want 0", got)
+	}
+	if got := i6.Get(); got != 0 {
+		t.Errorf("nil Int64Value.Get() = %v, want 0", got)
+	}
+	if got := u3.Get(); got != 0 {
+		t.Errorf("nil UInt32Value.Get() = %v, want 0", got)
+	}
+	if got := u6.Get(); got != 0 {
+		t.Errorf("nil UInt64Value.Get() = %v, want 0", got)
+	}
+	if got := f.Get(); got != 0 {
+		t.Errorf("nil FloatValue.Get() = %v, want 0", got)
+	}
+	if got := d.Get(); got != 0 {
+		t.Errorf("nil DoubleValue.Get() = %v, want 0", got)
+	}
+	if got := s.Get(); got != "" {
+		t.Errorf("nil StringValue.Get() = %q, want \"\"", got)
+	}
+	if got := by.Get(); got != nil {
+		t.Errorf("nil BytesValue.Get() = %v, want nil", got)
+	}
+}